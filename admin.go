@@ -0,0 +1,30 @@
+package main
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 管理用APIのトークン（未設定なら管理用APIは無効）
+func adminToken() string {
+	return siteConfig.Secrets.AdminToken
+}
+
+// 管理用APIの認証（Authorization: Bearer <ADMIN_TOKEN>）。無効時は存在しないものとして扱う
+func requireAdmin(c *gin.Context) {
+	token := adminToken()
+	if token == "" {
+		renderError(c, http.StatusNotFound, "ページが見つかりません", "お探しのページは存在しないか、移動された可能性があります。", nil)
+		return
+	}
+	given, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+		c.Header("WWW-Authenticate", `Bearer realm="admin"`)
+		renderError(c, http.StatusUnauthorized, "認証が必要です", "管理用APIにはトークンが必要です。", nil)
+		return
+	}
+	c.Next()
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// /blog/2024 形式の年指定
+var yearPattern = regexp.MustCompile(`^\d{4}$`)
+
+// /blog/2024/06 形式の月指定
+var monthPattern = regexp.MustCompile(`^(0?[1-9]|1[0-2])$`)
+
+// アーカイブ一覧（年・月ごとの件数）
+func archiveIndexPage(c *gin.Context) {
+	c.HTML(http.StatusOK, "blog.html", gin.H{
+		"title":           "アーカイブ | infoHiroki",
+		"heading":         "🗓️ アーカイブ",
+		"page":            "blog",
+		"termIndex":       true,
+		"archive":         store.Snapshot().Archive(time.Now()),
+		"metaDescription": "infoHirokiブログの年月別アーカイブ",
+		"ogTitle":         "アーカイブ | infoHiroki",
+		"ogDescription":   "生成AI・DX導入支援の技術ブログ",
+		"ogType":          "website",
+		"jsonLD":          blogListJSONLD("/blog/archive", "アーカイブ"),
+	})
+}
+
+// 年別記事一覧
+func yearArchivePage(c *gin.Context, yearParam string) {
+	year, _ := strconv.Atoi(yearParam)
+	filter := postFilter{Year: year}
+	if !hasVisiblePosts(filter) {
+		renderNotFound(c)
+		return
+	}
+
+	renderBlogList(c, blogListing{
+		path:        fmt.Sprintf("/blog/%04d", year),
+		title:       fmt.Sprintf("%d年の記事一覧 | infoHiroki", year),
+		heading:     fmt.Sprintf("🗓️ %d年", year),
+		description: fmt.Sprintf("infoHirokiブログの%d年の記事一覧", year),
+		filter:      filter,
+	})
+}
+
+// 月別記事一覧
+func monthArchivePage(c *gin.Context) {
+	yearParam, monthParam := c.Param("slug"), c.Param("month")
+	if !yearPattern.MatchString(yearParam) || !monthPattern.MatchString(monthParam) {
+		renderNotFound(c)
+		return
+	}
+
+	year, _ := strconv.Atoi(yearParam)
+	month, _ := strconv.Atoi(monthParam)
+	filter := postFilter{Year: year, Month: month}
+	if !hasVisiblePosts(filter) {
+		renderNotFound(c)
+		return
+	}
+
+	renderBlogList(c, blogListing{
+		path:        fmt.Sprintf("/blog/%04d/%02d", year, month),
+		title:       fmt.Sprintf("%d年%d月の記事一覧 | infoHiroki", year, month),
+		heading:     fmt.Sprintf("🗓️ %d年%d月", year, month),
+		description: fmt.Sprintf("infoHirokiブログの%d年%d月の記事一覧", year, month),
+		filter:      filter,
+	})
+}
+
+// 条件に合う公開記事が1件以上あるか
+func hasVisiblePosts(filter postFilter) bool {
+	for _, post := range store.Snapshot().Visible(time.Now()) {
+		if filter.matches(&post) {
+			return true
+		}
+	}
+	return false
+}
+
+// アーカイブAPI（year・month指定時はカレンダー用に日付ごとの記事も返す）
+func archiveAPI(c *gin.Context) {
+	snap := store.Snapshot()
+	now := time.Now()
+
+	response := gin.H{"archive": snap.Archive(now)}
+
+	year, yearErr := strconv.Atoi(c.Query("year"))
+	month, monthErr := strconv.Atoi(c.Query("month"))
+	if yearErr == nil && monthErr == nil && month >= 1 && month <= 12 {
+		days := map[string][]gin.H{}
+		for _, post := range snap.InMonth(year, time.Month(month), now) {
+			day := post.CreatedDate.Format("2006-01-02")
+			days[day] = append(days[day], gin.H{
+				"slug":  post.Slug,
+				"title": post.Title,
+				"icon":  post.Icon,
+				"url":   "/blog/" + post.Slug,
+			})
+		}
+		response["year"] = year
+		response["month"] = month
+		response["days"] = days
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"infohiroki-go/src/content"
+	"infohiroki-go/src/models"
+)
+
+func seedArchivePosts(t *testing.T) {
+	jst := models.PostLocation
+	seedPosts(t,
+		models.BlogPost{Slug: "may-a", Title: "5月の記事A", Published: true, CreatedDate: time.Date(2024, 5, 10, 0, 0, 0, 0, jst)},
+		models.BlogPost{Slug: "may-b", Title: "5月の記事B", Published: true, CreatedDate: time.Date(2024, 5, 10, 12, 0, 0, 0, jst)},
+		// JSTの6月1日（UTCでは5月31日）
+		models.BlogPost{Slug: "june", Title: "6月の記事", Published: true, CreatedDate: time.Date(2024, 6, 1, 1, 0, 0, 0, jst)},
+		models.BlogPost{Slug: "old", Title: "去年の記事", Published: true, CreatedDate: time.Date(2023, 12, 31, 0, 0, 0, 0, jst)},
+		models.BlogPost{Slug: "draft", Title: "下書き", CreatedDate: time.Date(2022, 1, 1, 0, 0, 0, 0, jst)},
+		models.BlogPost{Slug: "scheduled", Title: "予約", Published: true, CreatedDate: time.Now().AddDate(1, 0, 0)},
+	)
+}
+
+func TestArchiveRoutes(t *testing.T) {
+	seedArchivePosts(t)
+
+	tests := []struct {
+		path    string
+		status  int
+		want    []string
+		notWant []string
+	}{
+		{"/blog/2024", http.StatusOK, []string{"5月の記事A", "6月の記事"}, []string{"去年の記事"}},
+		{"/blog/2024/05", http.StatusOK, []string{"5月の記事A", "5月の記事B"}, []string{"6月の記事"}},
+		{"/blog/2024/5", http.StatusOK, []string{"5月の記事A"}, nil},
+		{"/blog/2024/06", http.StatusOK, []string{"6月の記事"}, []string{"5月の記事A"}},
+		{"/blog/2023/12", http.StatusOK, []string{"去年の記事"}, nil},
+		{"/blog/archive", http.StatusOK, []string{"/blog/2024/05", "/blog/2023/12"}, []string{"/blog/2022"}},
+		// 記事のない年月・下書きだけの年・不正な月は404
+		{"/blog/2024/07", http.StatusNotFound, nil, nil},
+		{"/blog/2022", http.StatusNotFound, nil, nil},
+		{"/blog/2024/13", http.StatusNotFound, nil, nil},
+		{"/blog/2024/00", http.StatusNotFound, nil, nil},
+		{"/blog/24/05", http.StatusNotFound, nil, nil},
+	}
+	for _, tt := range tests {
+		w := get(t, tt.path)
+		if w.Code != tt.status {
+			t.Errorf("%s: status %d, want %d", tt.path, w.Code, tt.status)
+			continue
+		}
+		body := w.Body.String()
+		for _, want := range tt.want {
+			if !strings.Contains(body, want) {
+				t.Errorf("%s: missing %q", tt.path, want)
+			}
+		}
+		for _, notWant := range tt.notWant {
+			if strings.Contains(body, notWant) {
+				t.Errorf("%s: unexpected %q", tt.path, notWant)
+			}
+		}
+	}
+}
+
+func TestArchiveAPI(t *testing.T) {
+	seedArchivePosts(t)
+
+	var body struct {
+		Archive []content.ArchiveYear `json:"archive"`
+		Days    map[string][]struct {
+			Slug string `json:"slug"`
+			URL  string `json:"url"`
+		} `json:"days"`
+	}
+	w := get(t, "/api/archive?year=2024&month=5")
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+
+	// 新しい年・月が先、予約投稿・下書きは数えない
+	if len(body.Archive) != 2 || body.Archive[0].Year != 2024 || body.Archive[0].Count != 3 || body.Archive[1].Count != 1 {
+		t.Fatalf("archive = %+v", body.Archive)
+	}
+	if months := body.Archive[0].Months; len(months) != 2 || months[0].Month != 6 || months[1] != (content.ArchiveMonth{Year: 2024, Month: 5, Count: 2}) {
+		t.Errorf("2024 months = %+v", months)
+	}
+	if day := body.Days["2024-05-10"]; len(day) != 2 || day[0].URL != "/blog/"+day[0].Slug {
+		t.Errorf("days = %+v", body.Days)
+	}
+
+	// 年月の指定がなければ日付ごとの記事は返さない
+	w = get(t, "/api/archive?year=2024&month=13")
+	if strings.Contains(w.Body.String(), `"days"`) {
+		t.Errorf("invalid month returned days: %s", w.Body.String())
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"infohiroki-go/src/config"
+	"infohiroki-go/src/content"
+	"infohiroki-go/src/images"
+	"infohiroki-go/src/ogimage"
+)
+
+// サイトの設定（main で読み込んだ設定を useConfig で反映する。それまでは既定値）
+var siteConfig config.Config
+
+func init() {
+	cfg := config.Default()
+	cfg.FillDerived()
+	useConfig(cfg)
+}
+
+// 設定を反映し、設定から作るストア・記事セット・画像キャッシュを作り直す（サーバー起動前に呼ぶ）
+func useConfig(cfg config.Config) {
+	siteConfig = cfg
+	siteBaseURL = cfg.Site.BaseURL
+	store = content.NewStoreWithOptions(storeOptions())
+	articles = newArticleSet(cfg.Content.ArticlesDir, cfg.Content.TaxonomyFile, cfg.Content.RedirectsFile)
+	imagePipeline = images.New("static/images/note", "/images/note", cfg.Cache.ImageDir, imageVariantPath, imageWidths)
+	ogCache = ogimage.NewCache(cfg.Cache.OGDir)
+}
+
+// 設定を読み込む（CONFIG_FILE のファイル（既定 config.yml）、SITE_ENV があれば config.<SITE_ENV>.yml で上書き、最後に環境変数）
+func loadConfig() (config.Config, error) {
+	path, ok := os.LookupEnv("CONFIG_FILE")
+	if !ok {
+		path = "config.yml"
+	}
+	paths := []string{path}
+	if env := os.Getenv("SITE_ENV"); env != "" {
+		// 環境別のファイルは指定された以上は必須
+		envPath := "config." + env + ".yml"
+		if _, err := os.Stat(envPath); err != nil {
+			return config.Config{}, fmt.Errorf("SITE_ENV=%s: %w", env, err)
+		}
+		paths = append(paths, envPath)
+	}
+	return config.Load(paths...)
+}
+
+// 起動時に主な設定を表示
+func logConfig() {
+	analytics := "無効"
+	if id := siteConfig.Analytics.GoogleAnalyticsID; id != "" {
+		analytics = id
+	}
+	fmt.Printf("⚙️ 設定: %s（記事: %s、アナリティクス: %s）\n", siteConfig.Site.BaseURL, siteConfig.Content.ArticlesDir, analytics)
+}
+
+// テンプレートから参照する設定
+func configFuncs(cfg config.Config) map[string]interface{} {
+	return map[string]interface{}{
+		"siteURL":     func() string { return cfg.Site.BaseURL },
+		"analyticsID": func() string { return cfg.Analytics.GoogleAnalyticsID },
+	}
+}
+
+// 生成画像の Cache-Control
+func cacheControl() string {
+	return "public, max-age=" + strconv.Itoa(siteConfig.Cache.MaxAge)
+}
+
+// robots.txt（サイトマップのURLは設定の公開URL。クロール拒否の設定なら全体を拒否）
+func robotsTxt(c *gin.Context) {
+	if !siteConfig.Features.Indexing {
+		c.String(http.StatusOK, "User-agent: *\nDisallow: /\n")
+		return
+	}
+	data, err := os.ReadFile("static/robots.txt")
+	if err != nil {
+		renderNotFound(c)
+		return
+	}
+	body := strings.ReplaceAll(string(data), "{{siteURL}}", siteBaseURL)
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(body))
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// 設定ファイルの誤りは起動時のエラーとして返す（読み込むまでは既定値のまま）
+func TestLoadConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+	broken := filepath.Join(dir, "broken.yml")
+	if err := os.WriteFile(broken, []byte("site:\n  prot: \"80\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("CONFIG_FILE", broken)
+	if _, err := loadConfig(); err == nil {
+		t.Error("unknown key: want an error")
+	}
+
+	t.Setenv("CONFIG_FILE", filepath.Join(dir, "none.yml"))
+	t.Setenv("SITE_ENV", "no-such-env")
+	if _, err := loadConfig(); err == nil {
+		t.Error("missing SITE_ENV file: want an error")
+	}
+}
+
+func TestUseConfig(t *testing.T) {
+	t.Setenv("SITE_ENV", "")
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yml")
+	data := "site:\n  base_url: https://example.com/\ncontent:\n  articles_dir: " + dir + "\ncache:\n  og_dir: " + filepath.Join(dir, "og") + "\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_FILE", path)
+
+	cfg, err := loadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := siteConfig
+	useConfig(cfg)
+	t.Cleanup(func() { useConfig(saved) })
+
+	if siteBaseURL != "https://example.com" {
+		t.Errorf("siteBaseURL = %q", siteBaseURL)
+	}
+	if articles.dir != dir || articles.rulesPath != filepath.Join(dir, "taxonomy.yml") {
+		t.Errorf("articles read from %q with rules %q", articles.dir, articles.rulesPath)
+	}
+	if got := configFuncs(cfg)["siteURL"].(func() string)(); got != "https://example.com" {
+		t.Errorf("siteURL in templates = %q", got)
+	}
+}
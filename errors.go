@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"infohiroki-go/src/search"
+)
+
+// 404ページに示す似た記事の最大数
+const notFoundSuggestionLimit = 5
+
+// 候補にする編集距離の上限（長い方の文字数に対する割合）
+const suggestionMaxDistance = 0.4
+
+// 候補探しに使うURLの文字数（これより長い部分は切り捨て、この倍を超えるURLは候補を探さない）
+const suggestionMaxQuery = 64
+
+// スラッグ先頭の日付（日付を省いたURLでも候補に出す）
+var slugDatePattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}-`)
+
+// 404ページの「もしかして」の候補
+type postSuggestion struct {
+	Slug  string `json:"slug"`
+	Title string `json:"title"`
+	Icon  string `json:"icon,omitempty"` // 絵文字のみ（画像アイコンは省く）
+	URL   string `json:"url"`
+}
+
+// エラー応答の形式（/api/ と .json は JSON、.md は Markdown、それ以外は Accept ヘッダーで選ぶ）
+func errorFormat(c *gin.Context) string {
+	p := c.Request.URL.Path
+	switch {
+	case strings.HasPrefix(p, "/api/") || strings.HasSuffix(p, ".json"):
+		return formatJSON
+	case strings.HasSuffix(p, ".md"):
+		return formatMarkdown
+	}
+	return negotiate(c)
+}
+
+// エラー応答（HTMLは error.html、Markdown・JSONは同じ内容をテキストで返す）
+func renderError(c *gin.Context, status int, heading string, message string, suggestions []postSuggestion) {
+	switch errorFormat(c) {
+	case formatJSON:
+		body := gin.H{"error": heading, "message": message, "status": status}
+		if len(suggestions) > 0 {
+			body["suggestions"] = suggestions
+		}
+		c.AbortWithStatusJSON(status, body)
+	case formatMarkdown:
+		var b strings.Builder
+		fmt.Fprintf(&b, "# %d %s\n\n%s\n", status, heading, message)
+		if len(suggestions) > 0 {
+			b.WriteString("\n## もしかして\n\n")
+			for _, s := range suggestions {
+				b.WriteString(llmsLink(s.Title, s.URL+".md", ""))
+			}
+		}
+		c.Data(status, markdownContentType, []byte(b.String()))
+		c.Abort()
+	default:
+		c.HTML(status, "error.html", gin.H{
+			"title":       fmt.Sprintf("%d - %s | infoHiroki", status, heading),
+			"page":        "error",
+			"status":      status,
+			"heading":     heading,
+			"message":     message,
+			"suggestions": suggestions,
+		})
+		c.Abort()
+	}
+}
+
+// 404（リダイレクト設定にあれば転送。なければ似たスラッグ・タイトルの記事を候補に示し、リダイレクト設定の参考にログへ残す）
+func renderNotFound(c *gin.Context) {
+	// 旧URL・記事の旧スラッグは転送
+	if serveRedirect(c) {
+		return
+	}
+
+	suggestions := suggestPosts(missedSlug(c), notFoundSuggestionLimit)
+
+	slugs := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		slugs[i] = s.Slug
+	}
+	log.Printf("🔍 404: %s (referer: %q, 候補: %s)", c.Request.URL.Path, c.Request.Referer(), strings.Join(slugs, ", "))
+
+	heading, message := "ページが見つかりません", "お探しのページは存在しないか、移動された可能性があります。"
+	if c.Param("slug") != "" {
+		heading, message = "記事が見つかりません", "お探しの記事は存在しないか、移動された可能性があります。"
+	}
+	renderError(c, http.StatusNotFound, heading, message, suggestions)
+}
+
+// 500（パニックからの復帰時）
+func renderInternalError(c *gin.Context, err any) {
+	log.Printf("⚠️ 内部エラー: %s: %v", c.Request.URL.Path, err)
+	renderError(c, http.StatusInternalServerError, "エラーが発生しました", "しばらくしてから再度お試しください。", nil)
+}
+
+// 見つからなかった記事のスラッグ（ルートのパラメータ、なければURLの末尾）
+func missedSlug(c *gin.Context) string {
+	slug := c.Param("slug")
+	if slug == "" {
+		slug = path.Base(c.Request.URL.Path)
+	}
+	for _, ext := range []string{".md", ".json", ".html", ".htm"} {
+		slug = strings.TrimSuffix(slug, ext)
+	}
+	return strings.ToLower(slug)
+}
+
+// スラッグ（日付の有無を問わない）・タイトルとの編集距離が近い公開記事を近い順に返す
+func suggestPosts(query string, limit int) []postSuggestion {
+	// 編集距離の計算量は文字数の積なので、スラッグより極端に長いURLは比べない
+	n := utf8.RuneCountInString(query)
+	if n < 3 || n > 2*suggestionMaxQuery {
+		return nil
+	}
+	if n > suggestionMaxQuery {
+		query = string([]rune(query)[:suggestionMaxQuery])
+	}
+
+	type candidate struct {
+		suggestion postSuggestion
+		distance   float64
+		created    time.Time
+	}
+	var candidates []candidate
+	for _, post := range store.Snapshot().Visible(time.Now()) {
+		best := 1.0
+		for _, text := range []string{post.Slug, slugDatePattern.ReplaceAllString(post.Slug, ""), strings.ToLower(post.Title)} {
+			if d := relativeDistance(query, text); d < best {
+				best = d
+			}
+		}
+		if best > suggestionMaxDistance {
+			continue
+		}
+		suggestion := postSuggestion{Slug: post.Slug, Title: post.Title, URL: siteBaseURL + "/blog/" + post.Slug}
+		if !post.IsIconURL() {
+			suggestion.Icon = post.Icon
+		}
+		candidates = append(candidates, candidate{suggestion: suggestion, distance: best, created: post.CreatedDate})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].created.After(candidates[j].created)
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	suggestions := make([]postSuggestion, len(candidates))
+	for i, candidate := range candidates {
+		suggestions[i] = candidate.suggestion
+	}
+	return suggestions
+}
+
+// 編集距離を長い方の文字数で割った値（0 は一致、1 はまったく異なる）
+func relativeDistance(a, b string) float64 {
+	n := max(utf8.RuneCountInString(a), utf8.RuneCountInString(b))
+	if n == 0 {
+		return 0
+	}
+	return float64(search.Distance(a, b)) / float64(n)
+}
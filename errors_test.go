@@ -0,0 +1,33 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"infohiroki-go/src/models"
+)
+
+func TestSuggestPostsLongPath(t *testing.T) {
+	store.ReplacePosts([]models.BlogPost{{
+		Slug:        "2024-05-19-ai-prompt-design-guide",
+		Title:       "AIプロンプト設計ガイド",
+		Published:   true,
+		CreatedDate: time.Date(2024, 5, 19, 0, 0, 0, 0, time.UTC),
+	}})
+	defer store.ReplacePosts(nil)
+
+	if got := suggestPosts("ai-prompt-design-guid", 5); len(got) != 1 {
+		t.Fatalf("suggestPosts(near slug) = %d suggestions, want 1", len(got))
+	}
+
+	// 巨大なパスは比べずに返す
+	start := time.Now()
+	if got := suggestPosts(strings.Repeat("a", 1<<20), 5); got != nil {
+		t.Errorf("suggestPosts(huge path) = %v, want nil", got)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("suggestPosts(huge path) took %v", elapsed)
+	}
+
+}
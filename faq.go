@@ -0,0 +1,53 @@
+package main
+
+import "html/template"
+
+// よくある質問（/faq の本文と構造化データの両方に使う）
+type faqItem struct {
+	Icon     string
+	Question string
+	Answer   template.HTML // 強調（<strong>）を含む
+}
+
+var faqItems = []faqItem{
+	{
+		Icon:     "🏢",
+		Question: "どのような業界・規模の企業が対象ですか？",
+		Answer:   `主に<strong>中小企業・スタートアップ</strong>を対象としています。業界問わず対応可能で、特に<strong>医療・建設・IT・製造・サービス業</strong>での生成AI導入実績が豊富です。桜十字病院でのWhisper活用から中小企業のChatGPT導入まで、多種多様な業界での生成AI導入を支援しています。`,
+	},
+	{
+		Icon:     "📋",
+		Question: "サービス開始までの流れを教えてください",
+		Answer:   `①<strong>LINEでお問い合わせ</strong> → ②<strong>AI導入相談（2万円・1.5時間）</strong> → ③<strong>現状分析レポート＋導入提案書の提出</strong> → ④<strong>契約（プロジェクトまたは顧問）</strong> → ⑤<strong>サービス開始</strong>の流れです。まずはAI導入相談で具体的な効果と費用をご確認ください。`,
+	},
+	{
+		Icon:     "💰",
+		Question: "料金プランと追加費用について教えてください",
+		Answer:   `<strong>技術顧問サービス：月額5万円</strong>（6-12ヶ月契約）、<strong>生成AI導入プロジェクト：20-500万円</strong>（企業規模に応じて）、<strong>AI導入相談：1回2万円</strong>（1.5時間）です。基本的に追加費用はかかりませんが、特別なソフトウェアライセンスが必要な場合は事前にご相談いたします。`,
+	},
+	{
+		Icon:     "⏰",
+		Question: "契約期間の縛りはありますか？",
+		Answer:   `<strong>生成AI導入プロジェクトは2-6ヶ月</strong>（規模により）、<strong>技術顧問サービスは6ヶ月または12ヶ月の契約期間</strong>があります。<strong>AI導入相談は単発のため契約期間の縛りはありません。</strong>`,
+	},
+	{
+		Icon:     "💻",
+		Question: "オンラインでの対応は可能ですか？",
+		Answer:   `はい、全国どこでもオンラインで対応可能です。Zoom、Teams、Google Meet等のツールを使用し、画面共有やリモート操作でサポートします。`,
+	},
+	{
+		Icon:     "🔒",
+		Question: "データの安全性は保障されますか？",
+		Answer:   `お客様のデータは厳重に管理し、機密保持契約（NDA）の締結も可能です。作業終了後はお客様のデータを完全削除いたします。`,
+	},
+	{
+		Icon:     "🤖",
+		Question: "どのようなAIツールに対応していますか？",
+		Answer:   `<strong>ChatGPT、Claude、Gemini</strong>等の大規模言語モデル、<strong>音声認識AI（Whisper等）</strong>、<strong>Google Apps Script、Excel VBA</strong>、<strong>Notion、音声文字起こしシステム</strong>等、幅広いツールに対応しています。実際の導入実績に基づいて最適なツールをご提案します。`,
+	},
+	{
+		Icon:     "❓",
+		Question: "技術的な知識がなくても大丈夫ですか？",
+		Answer:   `はい、技術的な知識は不要です。業務の課題や改善したい点をお聞かせいただければ、技術的な部分は全てお任せください。操作方法も丁寧にレクチャーいたします。`,
+	},
+}
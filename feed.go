@@ -0,0 +1,316 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"infohiroki-go/src/models"
+)
+
+// フィードに含める最新記事の件数
+const feedLimit = 20
+
+const (
+	feedTitle       = "infoHiroki ブログ"
+	feedDescription = "生成AI・DX導入支援の技術ブログ"
+	feedAuthor      = "infoHiroki"
+)
+
+// 本文中のルート相対URL（/images/... 等）
+var relativeURLPattern = regexp.MustCompile(`(src|href)="/([^/"][^"]*)?"`)
+
+// srcset 内のルート相対URL（"/img/a-400.png 400w, /img/a-800.png 800w"）
+var srcsetPattern = regexp.MustCompile(`srcset="[^"]*"`)
+var srcsetURLPattern = regexp.MustCompile(`(^srcset="|, )/([^/ ])`)
+
+// RSS 2.0
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Content string     `xml:"xmlns:content,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	AtomLink      feedLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title          string   `xml:"title"`
+	Link           string   `xml:"link"`
+	GUID           rssGUID  `xml:"guid"`
+	Description    string   `xml:"description"`
+	ContentEncoded xmlCDATA `xml:"content:encoded"`
+	PubDate        string   `xml:"pubDate"`
+	Categories     []string `xml:"category,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type xmlCDATA struct {
+	Value string `xml:",cdata"`
+}
+
+// Atom
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Lang     string      `xml:"xml:lang,attr"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle"`
+	ID       string      `xml:"id"`
+	Updated  string      `xml:"updated"`
+	Links    []feedLink  `xml:"link"`
+	Author   atomAuthor  `xml:"author"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type feedLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	Title     string         `xml:"title"`
+	ID        string         `xml:"id"`
+	Links     []feedLink     `xml:"link"`
+	Published string         `xml:"published"`
+	Updated   string         `xml:"updated"`
+	Summary   string         `xml:"summary"`
+	Content   atomContent    `xml:"content"`
+	Category  []atomCategory `xml:"category,omitempty"`
+}
+
+type atomContent struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// JSON Feed 1.1
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Description string         `json:"description"`
+	Language    string         `json:"language"`
+	Authors     []jsonFeedName `json:"authors"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedName struct {
+	Name string `json:"name"`
+}
+
+type jsonFeedItem struct {
+	ID            string   `json:"id"`
+	URL           string   `json:"url"`
+	Title         string   `json:"title"`
+	ContentHTML   string   `json:"content_html"`
+	Summary       string   `json:"summary,omitempty"`
+	Image         string   `json:"image,omitempty"`
+	DatePublished string   `json:"date_published"`
+	DateModified  string   `json:"date_modified"`
+	Tags          []string `json:"tags,omitempty"`
+}
+
+// フィード対象の記事（公開済みの新しい順）
+func feedPosts() []models.BlogPost {
+	posts := store.Snapshot().Visible(time.Now())
+	if len(posts) > feedLimit {
+		posts = posts[:feedLimit]
+	}
+	return posts
+}
+
+// 記事本文をHTML化し、相対URLを絶対URLに変換
+func feedContent(post *models.BlogPost) string {
+	html := string(post.RenderContent())
+	html = relativeURLPattern.ReplaceAllString(html, `$1="`+siteBaseURL+`/$2"`)
+	return srcsetPattern.ReplaceAllStringFunc(html, func(srcset string) string {
+		return srcsetURLPattern.ReplaceAllString(srcset, `${1}`+siteBaseURL+`/$2`)
+	})
+}
+
+// 記事の更新日時（未設定なら作成日）
+func postUpdated(post *models.BlogPost) time.Time {
+	if post.UpdatedAt.After(post.CreatedDate) {
+		return post.UpdatedAt
+	}
+	return post.CreatedDate
+}
+
+// 記事の代表画像（絶対URL）
+func postImageURL(post *models.BlogPost) string {
+	if post.OGImage != "" {
+		return absoluteURL(post.OGImage)
+	}
+	// OGP画像の指定がなければ本文中の最初の画像
+	return absoluteURL(post.Rendered().Stats.FirstImage)
+}
+
+// ルート相対パスを絶対URLに変換
+func absoluteURL(path string) string {
+	if len(path) > 0 && path[0] == '/' {
+		return siteBaseURL + path
+	}
+	return path
+}
+
+// フィード全体の最終更新日時
+func feedUpdated(posts []models.BlogPost) time.Time {
+	var updated time.Time
+	for i := range posts {
+		if t := postUpdated(&posts[i]); t.After(updated) {
+			updated = t
+		}
+	}
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+	return updated
+}
+
+// RSS 2.0 フィード
+func rssFeedXML(c *gin.Context) {
+	posts := feedPosts()
+
+	feed := rssFeed{
+		Version: "2.0",
+		Content: "http://purl.org/rss/1.0/modules/content/",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         feedTitle,
+			Link:          siteBaseURL + "/blog",
+			Description:   feedDescription,
+			Language:      "ja",
+			LastBuildDate: feedUpdated(posts).Format(time.RFC1123Z),
+			AtomLink:      feedLink{Href: siteBaseURL + "/feed.xml", Rel: "self", Type: "application/rss+xml"},
+		},
+	}
+
+	for i := range posts {
+		post := &posts[i]
+		link := siteBaseURL + "/blog/" + post.Slug
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{
+			Title:          post.Title,
+			Link:           link,
+			GUID:           rssGUID{IsPermaLink: true, Value: link},
+			Description:    post.Description,
+			ContentEncoded: xmlCDATA{Value: feedContent(post)},
+			PubDate:        post.CreatedDate.Format(time.RFC1123Z),
+			Categories:     post.Tags,
+		})
+	}
+
+	writeFeedXML(c, "application/rss+xml; charset=utf-8", feed)
+}
+
+// Atom フィード
+func atomFeedXML(c *gin.Context) {
+	posts := feedPosts()
+
+	feed := atomFeed{
+		Lang:     "ja",
+		Title:    feedTitle,
+		Subtitle: feedDescription,
+		ID:       siteBaseURL + "/blog",
+		Updated:  feedUpdated(posts).Format(time.RFC3339),
+		Links: []feedLink{
+			{Href: siteBaseURL + "/atom.xml", Rel: "self", Type: "application/atom+xml"},
+			{Href: siteBaseURL + "/blog", Rel: "alternate", Type: "text/html"},
+		},
+		Author: atomAuthor{Name: feedAuthor},
+	}
+
+	for i := range posts {
+		post := &posts[i]
+		link := siteBaseURL + "/blog/" + post.Slug
+		entry := atomEntry{
+			Title:     post.Title,
+			ID:        link,
+			Links:     []feedLink{{Href: link, Rel: "alternate", Type: "text/html"}},
+			Published: post.CreatedDate.Format(time.RFC3339),
+			Updated:   postUpdated(post).Format(time.RFC3339),
+			Summary:   post.Description,
+			Content:   atomContent{Type: "html", Value: feedContent(post)},
+		}
+		for _, tag := range post.Tags {
+			entry.Category = append(entry.Category, atomCategory{Term: tag})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	writeFeedXML(c, "application/atom+xml; charset=utf-8", feed)
+}
+
+// JSON Feed 1.1
+func jsonFeedHandler(c *gin.Context) {
+	posts := feedPosts()
+
+	feed := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       feedTitle,
+		HomePageURL: siteBaseURL + "/blog",
+		FeedURL:     siteBaseURL + "/feed.json",
+		Description: feedDescription,
+		Language:    "ja",
+		Authors:     []jsonFeedName{{Name: feedAuthor}},
+		Items:       []jsonFeedItem{},
+	}
+
+	for i := range posts {
+		post := &posts[i]
+		link := siteBaseURL + "/blog/" + post.Slug
+		feed.Items = append(feed.Items, jsonFeedItem{
+			ID:            link,
+			URL:           link,
+			Title:         post.Title,
+			ContentHTML:   feedContent(post),
+			Summary:       post.Description,
+			Image:         postImageURL(post),
+			DatePublished: post.CreatedDate.Format(time.RFC3339),
+			DateModified:  postUpdated(post).Format(time.RFC3339),
+			Tags:          post.Tags,
+		})
+	}
+
+	body, err := json.Marshal(feed)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "フィードの生成に失敗しました")
+		return
+	}
+	c.Data(http.StatusOK, "application/feed+json; charset=utf-8", body)
+}
+
+// XMLフィードを書き出し
+func writeFeedXML(c *gin.Context, contentType string, feed interface{}) {
+	body, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		c.String(http.StatusInternalServerError, "フィードの生成に失敗しました")
+		return
+	}
+	c.Data(http.StatusOK, contentType, append([]byte(xml.Header), body...))
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"infohiroki-go/src/markdown"
+	"infohiroki-go/src/models"
+)
+
+func TestFeedContentAbsoluteURLs(t *testing.T) {
+	saved := markdown.Default
+	markdown.Default = markdown.New(markdown.WithImageResolver(func(src string) (markdown.ResponsiveImage, bool) {
+		return markdown.ResponsiveImage{
+			Width:      800,
+			Height:     600,
+			SrcSet:     "/img/a-400.png 400w, /img/a-800.png 800w",
+			WebPSrcSet: "/img/a-400.webp 400w, /img/a-800.webp 800w",
+		}, true
+	}))
+	defer func() { markdown.Default = saved }()
+
+	post := &models.BlogPost{Content: "![図](/images/note/a.png)\n\n[記事](/blog/x) [CDN](//cdn.example.com/x) [外部](https://example.com/y)\n"}
+	html := feedContent(post)
+
+	for _, want := range []string{
+		`src="` + siteBaseURL + `/images/note/a.png"`,
+		`srcset="` + siteBaseURL + `/img/a-400.png 400w, ` + siteBaseURL + `/img/a-800.png 800w"`,
+		`srcset="` + siteBaseURL + `/img/a-400.webp 400w, ` + siteBaseURL + `/img/a-800.webp 800w"`,
+		`href="` + siteBaseURL + `/blog/x"`,
+		`href="//cdn.example.com/x"`,
+		`href="https://example.com/y"`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("missing %s in\n%s", want, html)
+		}
+	}
+	if strings.Contains(html, `"/img/`) || strings.Contains(html, ` /img/`) {
+		t.Errorf("relative image URL left in\n%s", html)
+	}
+}
+
+func TestPostUpdated(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	updated := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		updatedAt time.Time
+		want      time.Time
+	}{
+		{time.Time{}, created},
+		{updated, updated},
+		{created.Add(-time.Hour), created}, // 作成日より前の更新日は無視
+	}
+	for _, tt := range tests {
+		post := &models.BlogPost{CreatedDate: created, UpdatedAt: tt.updatedAt}
+		if got := postUpdated(post); !got.Equal(tt.want) {
+			t.Errorf("postUpdated(updated %v) = %v, want %v", tt.updatedAt, got, tt.want)
+		}
+	}
+}
+
+// フィード用に記事を入れ替える（feed-00 が最も古い。下書き・予約投稿を1件ずつ含む）
+func seedFeedPosts(t *testing.T, n int) {
+	t.Helper()
+	posts := make([]models.BlogPost, n, n+2)
+	for i := range posts {
+		posts[i] = models.BlogPost{
+			Slug:        fmt.Sprintf("feed-%02d", i),
+			Title:       fmt.Sprintf("記事 %02d", i),
+			Content:     "本文",
+			Tags:        []string{"go"},
+			Published:   true,
+			CreatedDate: time.Date(2024, 1, 1+i, 0, 0, 0, 0, time.UTC),
+		}
+	}
+	posts[n-1].UpdatedAt = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	posts = append(posts,
+		models.BlogPost{Slug: "draft", Title: "下書き", CreatedDate: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		models.BlogPost{Slug: "scheduled", Title: "予約", Published: true, CreatedDate: time.Now().Add(24 * time.Hour)},
+	)
+	store.ReplacePosts(posts)
+	t.Cleanup(func() { store.ReplacePosts(nil) })
+}
+
+func getFeed(t *testing.T, handler gin.HandlerFunc, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, path, nil)
+	handler(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("%s: status %d", path, w.Code)
+	}
+	return w
+}
+
+func TestRSSFeed(t *testing.T) {
+	seedFeedPosts(t, feedLimit+5)
+
+	w := getFeed(t, rssFeedXML, "/feed.xml")
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/rss+xml") {
+		t.Errorf("content type = %q", ct)
+	}
+	var feed rssFeed
+	if err := xml.Unmarshal(w.Body.Bytes(), &feed); err != nil {
+		t.Fatal(err)
+	}
+	items := feed.Channel.Items
+	if len(items) != feedLimit {
+		t.Fatalf("%d items, want %d", len(items), feedLimit)
+	}
+	// 新しい順で、下書き・予約投稿は含めない
+	if want := siteBaseURL + "/blog/feed-24"; items[0].Link != want || items[0].GUID.Value != want {
+		t.Errorf("first item = %s, want %s", items[0].Link, want)
+	}
+	for _, item := range items {
+		if strings.HasSuffix(item.Link, "/draft") || strings.HasSuffix(item.Link, "/scheduled") {
+			t.Errorf("unpublished post %s in feed", item.Link)
+		}
+	}
+	if items[0].PubDate != "Thu, 25 Jan 2024 00:00:00 +0000" {
+		t.Errorf("pubDate = %q", items[0].PubDate)
+	}
+	if feed.Channel.LastBuildDate != "Sat, 01 Jun 2024 00:00:00 +0000" {
+		t.Errorf("lastBuildDate = %q, want the latest update", feed.Channel.LastBuildDate)
+	}
+}
+
+func TestAtomFeed(t *testing.T) {
+	seedFeedPosts(t, 3)
+
+	w := getFeed(t, atomFeedXML, "/atom.xml")
+	var feed atomFeed
+	if err := xml.Unmarshal(w.Body.Bytes(), &feed); err != nil {
+		t.Fatal(err)
+	}
+	if len(feed.Entries) != 3 {
+		t.Fatalf("%d entries, want 3", len(feed.Entries))
+	}
+	entry := feed.Entries[0]
+	if entry.Published != "2024-01-03T00:00:00Z" || entry.Updated != "2024-06-01T00:00:00Z" {
+		t.Errorf("published %s, updated %s", entry.Published, entry.Updated)
+	}
+	if feed.Updated != "2024-06-01T00:00:00Z" {
+		t.Errorf("feed updated = %s", feed.Updated)
+	}
+	if len(entry.Category) != 1 || entry.Category[0].Term != "go" {
+		t.Errorf("categories = %v", entry.Category)
+	}
+}
+
+func TestJSONFeed(t *testing.T) {
+	store.ReplacePosts(nil)
+
+	// 記事がなくても items は空配列
+	w := getFeed(t, jsonFeedHandler, "/feed.json")
+	if !strings.Contains(w.Body.String(), `"items":[]`) {
+		t.Errorf("empty feed: %s", w.Body.String())
+	}
+
+	seedFeedPosts(t, 2)
+	w = getFeed(t, jsonFeedHandler, "/feed.json")
+	var feed jsonFeed
+	if err := json.Unmarshal(w.Body.Bytes(), &feed); err != nil {
+		t.Fatal(err)
+	}
+	if feed.Version != "https://jsonfeed.org/version/1.1" || len(feed.Items) != 2 {
+		t.Fatalf("version %s, %d items", feed.Version, len(feed.Items))
+	}
+	if item := feed.Items[0]; item.ID != siteBaseURL+"/blog/feed-01" || item.DateModified != "2024-06-01T00:00:00Z" {
+		t.Errorf("first item = %+v", item)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"infohiroki-go/src/images"
+	"infohiroki-go/src/markdown"
+)
+
+// 記事画像の縮小版を作る幅（本文の表示幅800pxとその2倍まで）
+var imageWidths = []int{400, 800, 1200, 1600}
+
+// 生成した画像の配信パス（/images は静的ファイル配信が使うため別パス）
+const imageVariantPath = "/img"
+
+// 記事画像のパイプライン（cache.image_dir に縮小版・WebPを保存）
+var imagePipeline *images.Pipeline
+
+// 画像のサイズを読み込み、縮小版・WebPをバックグラウンドで生成（cache.warm_images が false なら初回アクセス時に生成）
+func initializeImages() {
+	if err := imagePipeline.Load(); err != nil {
+		log.Printf("⚠️ 記事画像を読み込めません: %v", err)
+		return
+	}
+	log.Printf("🖼️ 記事画像: %d枚", imagePipeline.Len())
+
+	if siteConfig.Cache.WarmImages {
+		go imagePipeline.Warm()
+	}
+}
+
+// 本文の画像URLから srcset・サイズを解決（Markdownレンダラー用）
+func resolveImage(src string) (markdown.ResponsiveImage, bool) {
+	info, ok := imagePipeline.Lookup(src)
+	if !ok {
+		return markdown.ResponsiveImage{}, false
+	}
+	original, webp := imagePipeline.Variants(info)
+	return markdown.ResponsiveImage{
+		Width:      info.Width,
+		Height:     info.Height,
+		SrcSet:     images.SrcSet(original),
+		WebPSrcSet: images.SrcSet(webp),
+	}, true
+}
+
+// 画像の縮小版・WebP（未生成ならその場で生成）
+func serveImageVariant(c *gin.Context) {
+	path, err := imagePipeline.File(c.Param("name"))
+	if errors.Is(err, os.ErrNotExist) {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("⚠️ 画像を生成できません: %s: %v", c.Param("name"), err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	c.Header("Cache-Control", cacheControl())
+	c.File(path)
+}
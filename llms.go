@@ -0,0 +1,141 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"infohiroki-go/src/content"
+	"infohiroki-go/src/models"
+)
+
+// llms.txt（https://llmstxt.org/）の見出しと概要
+const (
+	llmsTitle   = "infoHiroki"
+	llmsSummary = "中小企業・スタートアップ向けのDX・生成AI導入支援（infoHiroki）のサイトと技術ブログ。ブログ記事は各URLでMarkdown本文を取得できます。"
+)
+
+// カテゴリ未設定の記事の見出し
+const llmsUncategorized = "その他"
+
+// 生成済みの llms.txt / llms-full.txt（記事の再読み込み・予約投稿の公開で作り直す）
+var llmsFiles struct {
+	mu      sync.Mutex
+	snap    *content.Snapshot // 生成元のスナップショット
+	visible int               // 公開中の記事数（予約投稿の公開を検知する）
+	index   []byte
+	full    []byte
+}
+
+// 現在の記事から llms.txt と llms-full.txt を取得（変更がなければ前回の結果）
+func currentLLMSFiles() (index []byte, full []byte) {
+	snap := store.Snapshot()
+	posts := snap.Visible(time.Now())
+
+	llmsFiles.mu.Lock()
+	defer llmsFiles.mu.Unlock()
+	if llmsFiles.snap != snap || llmsFiles.visible != len(posts) {
+		llmsFiles.index, llmsFiles.full = buildLLMSFiles(snap, posts)
+		llmsFiles.snap, llmsFiles.visible = snap, len(posts)
+	}
+	return llmsFiles.index, llmsFiles.full
+}
+
+// /llms.txt（ページと記事の一覧）
+func llmsTxt(c *gin.Context) {
+	index, _ := currentLLMSFiles()
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", index)
+}
+
+// /llms-full.txt（記事本文を含む）
+func llmsFullTxt(c *gin.Context) {
+	_, full := currentLLMSFiles()
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", full)
+}
+
+// 記事のMarkdown版のURL
+func postMarkdownURL(post *models.BlogPost) string {
+	return siteBaseURL + "/blog/" + post.Slug + ".md"
+}
+
+// 記事をカテゴリごとにまとめる（カテゴリは記事数の多い順、未設定は最後）
+type llmsSection struct {
+	Name  string
+	Posts []models.BlogPost
+}
+
+func llmsSections(snap *content.Snapshot, posts []models.BlogPost) []llmsSection {
+	byCategory := map[string][]models.BlogPost{}
+	for _, post := range posts {
+		byCategory[post.Category] = append(byCategory[post.Category], post)
+	}
+
+	var sections []llmsSection
+	for _, term := range snap.Categories(time.Now()) {
+		if posts := byCategory[term.Name]; len(posts) > 0 {
+			sections = append(sections, llmsSection{Name: term.Name, Posts: posts})
+		}
+	}
+	if posts := byCategory[""]; len(posts) > 0 {
+		sections = append(sections, llmsSection{Name: llmsUncategorized, Posts: posts})
+	}
+	return sections
+}
+
+func buildLLMSFiles(snap *content.Snapshot, posts []models.BlogPost) (index []byte, full []byte) {
+	sections := llmsSections(snap, posts)
+
+	var header strings.Builder
+	header.WriteString("# " + llmsTitle + "\n\n")
+	header.WriteString("> " + llmsSummary + "\n\n")
+
+	// llms.txt: 固定ページと記事（カテゴリ別）のリンク一覧
+	var b strings.Builder
+	b.WriteString(header.String())
+	b.WriteString("## ページ\n\n")
+	for _, page := range staticPages {
+		b.WriteString(llmsLink(page.Name, siteBaseURL+"/"+page.Slug, page.Description))
+	}
+	for _, section := range sections {
+		b.WriteString("\n## " + section.Name + "\n\n")
+		for i := range section.Posts {
+			post := &section.Posts[i]
+			b.WriteString(llmsLink(post.Title, postMarkdownURL(post), post.Description))
+		}
+	}
+
+	// llms-full.txt: 固定ページの概要と記事のMarkdown本文
+	var f strings.Builder
+	f.WriteString(header.String())
+	f.WriteString("## ページ\n\n")
+	for _, page := range staticPages {
+		f.WriteString(llmsLink(page.Name, siteBaseURL+"/"+page.Slug, page.Description))
+	}
+	for _, section := range sections {
+		for i := range section.Posts {
+			post := &section.Posts[i]
+			f.WriteString("\n---\n\n")
+			f.WriteString("URL: " + siteBaseURL + "/blog/" + post.Slug + "\n")
+			f.WriteString("カテゴリ: " + section.Name + "\n\n")
+			f.WriteString(strings.TrimRight(post.ToMarkdown(), "\n") + "\n")
+		}
+	}
+
+	return []byte(b.String()), []byte(f.String())
+}
+
+// 「- [タイトル](URL): 説明」の1行
+func llmsLink(title, url, description string) string {
+	line := "- [" + llmsEscape(title) + "](" + url + ")"
+	if description = strings.Join(strings.Fields(description), " "); description != "" {
+		line += ": " + description
+	}
+	return line + "\n"
+}
+
+// リンクテキスト中の角括弧をエスケープ
+func llmsEscape(text string) string {
+	return strings.NewReplacer("[", `\[`, "]", `\]`).Replace(text)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"infohiroki-go/src/models"
+)
+
+func seedLLMSPosts(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	seedPosts(t,
+		models.BlogPost{Slug: "ai-1", Title: "生成AI [入門]", Description: "生成AIの\n基本", Category: "ai", Content: "AIの本文", Published: true, CreatedDate: created.AddDate(0, 0, 3)},
+		models.BlogPost{Slug: "ai-2", Title: "プロンプト設計", Category: "ai", Content: "プロンプトの本文", Published: true, CreatedDate: created.AddDate(0, 0, 2)},
+		models.BlogPost{Slug: "go-1", Title: "Go入門", Category: "dev", Content: "Goの本文", Published: true, CreatedDate: created.AddDate(0, 0, 1)},
+		models.BlogPost{Slug: "misc", Title: "雑記", Content: "雑記の本文", Published: true, CreatedDate: created},
+		models.BlogPost{Slug: "draft", Title: "下書き", Category: "ai", Content: "下書きの本文", CreatedDate: created},
+	)
+}
+
+func TestLLMSTxt(t *testing.T) {
+	seedLLMSPosts(t)
+
+	w := get(t, "/llms.txt")
+	if ct := w.Header().Get("Content-Type"); ct != "text/plain; charset=utf-8" {
+		t.Errorf("content type = %q", ct)
+	}
+	body := w.Body.String()
+
+	if !strings.HasPrefix(body, "# "+llmsTitle+"\n\n> "+llmsSummary+"\n\n## ページ\n\n") {
+		t.Errorf("header:\n%s", body)
+	}
+	for _, want := range []string{
+		"- [サービス](" + siteBaseURL + "/services): ",
+		// 角括弧はエスケープし、説明は1行にまとめる
+		"- [生成AI \\[入門\\]](" + siteBaseURL + "/blog/ai-1.md): 生成AIの 基本\n",
+		"- [Go入門](" + siteBaseURL + "/blog/go-1.md)\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("missing %q in\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "下書き") {
+		t.Error("draft listed")
+	}
+
+	// カテゴリは記事数の多い順、未設定は最後
+	ai, dev, misc := strings.Index(body, "\n## ai\n"), strings.Index(body, "\n## dev\n"), strings.Index(body, "\n## "+llmsUncategorized+"\n")
+	if ai < 0 || dev < 0 || misc < 0 || !(ai < dev && dev < misc) {
+		t.Errorf("sections at ai %d, dev %d, uncategorized %d:\n%s", ai, dev, misc, body)
+	}
+}
+
+func TestLLMSFullTxt(t *testing.T) {
+	seedLLMSPosts(t)
+
+	body := get(t, "/llms-full.txt").Body.String()
+	for _, want := range []string{
+		"URL: " + siteBaseURL + "/blog/ai-1\nカテゴリ: ai\n\n# 生成AI [入門]\n",
+		"プロンプトの本文\n",
+		"カテゴリ: " + llmsUncategorized + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("missing %q in\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "下書きの本文") {
+		t.Error("draft body included")
+	}
+}
+
+// 記事が差し替わったら作り直す
+func TestLLMSTxtRebuiltOnReload(t *testing.T) {
+	seedLLMSPosts(t)
+	if body := get(t, "/llms.txt").Body.String(); !strings.Contains(body, "Go入門") {
+		t.Fatal("first build missing post")
+	}
+
+	store.ReplacePosts([]models.BlogPost{{Slug: "new", Title: "新しい記事", Published: true, CreatedDate: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)}})
+	body := get(t, "/llms.txt").Body.String()
+	if strings.Contains(body, "Go入門") || !strings.Contains(body, "新しい記事") {
+		t.Errorf("stale llms.txt after reload:\n%s", body)
+	}
+}
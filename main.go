@@ -6,39 +6,107 @@ package main
 import (
 	"fmt"
 	"html/template"
+	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
+	"infohiroki-go/src/content"
+	"infohiroki-go/src/markdown"
 	"infohiroki-go/src/models"
+	"infohiroki-go/src/redirects"
+	"infohiroki-go/src/search"
 )
 
-// データはファイルベースで管理
-var allPosts []models.BlogPost
-var allPages []models.Page
+// データはファイルベースで管理（リロード時はスナップショットごと差し替え）
+var store *content.Store
+var articles *articleSet
 
+// サイトの公開URL（sitemap・フィード等の絶対URL生成用。環境ごとに設定で変える）
+var siteBaseURL string
+
+// 記事内のサイト内絶対URLをルート相対パスに変換（フィードでは絶対URLに戻す）
+func siteRelativeLink(dest string) string {
+	// 本文に本番URLで書かれたリンクも対象（content_urls）
+	for _, base := range append([]string{siteBaseURL}, siteConfig.Site.ContentURLs...) {
+		if dest == base {
+			return "/"
+		}
+		if strings.HasPrefix(dest, base+"/") {
+			return strings.TrimPrefix(dest, base)
+		}
+	}
+	return dest
+}
 
 func main() {
-	// データ初期化（ファイルベース）
+	// 設定の読み込み（不正な値があれば起動しない）
+	cfg, err := loadConfig()
+	if err != nil {
+		log.Fatalf("❌ 設定エラー: %v", err)
+	}
+	useConfig(cfg)
+	logConfig()
+
+	// 記事画像のサイズ読み込み（本文の srcset・width/height に使う）
+	initializeImages()
+
+	// 記事のMarkdownレンダラー（サイト内の絶対URLリンクは相対パスに書き換え、画像はレスポンシブ化）
+	markdown.Default = markdown.New(
+		markdown.WithLinkRewriter(siteRelativeLink),
+		markdown.WithImageResolver(resolveImage),
+	)
+
+	r := newRouter()
+
+	// データ初期化（ファイルベース）。リダイレクト元と既存ルートの重なりを検証するためルート登録後に行う
+	recordRoutes(r.Routes())
 	initializeData()
 
-	// Gin ルーター設定
-	r := gin.Default()
+	// 記事ディレクトリの変更を監視して自動リロード
+	if siteConfig.Features.WatchArticles {
+		if _, err := watchArticles(articles); err != nil {
+			fmt.Printf("⚠️ 記事ディレクトリの監視を開始できません: %v\n", err)
+		}
+	}
+
+	// サーバー起動
+	r.Run(":" + siteConfig.Site.Port)
+}
+
+// ルーターを組み立てる（テンプレート・静的ファイル・全ルート）
+func newRouter() *gin.Engine {
+	// Gin ルーター設定（パニック時もエラーページを返す）
+	r := gin.New()
+	r.Use(gin.Logger(), gin.CustomRecovery(renderInternalError))
 
 	// カスタムテンプレート関数を設定
-	r.SetFuncMap(template.FuncMap{
-		"hasPrefix": strings.HasPrefix,
-		"hasSuffix": strings.HasSuffix,
-	})
+	funcs := template.FuncMap{
+		"hasPrefix":  strings.HasPrefix,
+		"hasSuffix":  strings.HasSuffix,
+		"pathEscape": url.PathEscape,
+	}
+	for name, fn := range configFuncs(siteConfig) {
+		funcs[name] = fn
+	}
+	r.SetFuncMap(funcs)
 
 	// 静的ファイルの配信
 	r.Static("/css", "./static/css")
 	r.Static("/js", "./static/js")
 	r.Static("/images", "./static/images")
+	r.GET(imageVariantPath+"/:name", serveImageVariant)
+	if siteConfig.Features.OGImages {
+		r.GET("/og/:file", ogImage)
+	}
 
 	// テンプレート読み込み（base.htmlを含むすべてのテンプレート）
 	r.LoadHTMLGlob("templates/*.html")
@@ -47,6 +115,13 @@ func main() {
 	r.GET("/", homePage)
 	r.GET("/blog", blogList)
 	r.GET("/blog/:slug", handleBlogPost)
+	r.GET("/blog/tags", tagIndexPage)
+	r.GET("/blog/tags/:tag", tagPage)
+	r.GET("/blog/categories", categoryIndexPage)
+	r.GET("/blog/categories/:category", categoryPage)
+	r.GET("/blog/series/:name", seriesPage)
+	r.GET("/blog/archive", archiveIndexPage)
+	r.GET("/blog/:slug/:month", monthArchivePage)
 	r.GET("/services", servicesPage)
 	r.GET("/products", productsPage)
 	r.GET("/results", resultsPage)
@@ -54,43 +129,38 @@ func main() {
 	r.GET("/faq", faqPage)
 	r.GET("/contact", contactPage)
 
-	// 301リダイレクト: 旧URL構造対応
-	r.GET("/index.html", func(c *gin.Context) {
-		c.Redirect(http.StatusMovedPermanently, "/")
-	})
-	r.GET("/html-files/:filename", func(c *gin.Context) {
-		filename := c.Param("filename")
-		// .htmlを除去してスラッグ化
-		slug := strings.TrimSuffix(filename, ".html")
-		c.Redirect(http.StatusMovedPermanently, "/blog/"+slug)
-	})
-
 	// Health check endpoint for Railway/Cloudflare
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
 
 	// SEO endpoints
-	r.StaticFile("/robots.txt", "./static/robots.txt")
+	r.GET("/robots.txt", robotsTxt)
 	r.GET("/sitemap.xml", sitemapXML)
+	if siteConfig.Features.LLMSTxt {
+		r.GET("/llms.txt", llmsTxt)
+		r.GET("/llms-full.txt", llmsFullTxt)
+	}
+
+	// フィード
+	r.GET("/feed.xml", rssFeedXML)
+	r.GET("/atom.xml", atomFeedXML)
+	r.GET("/feed.json", jsonFeedHandler)
 
 	// API endpoints
 	r.GET("/api/search", searchBlogPosts)
-
-	// 404エラーハンドラー
-	r.NoRoute(func(c *gin.Context) {
-		c.HTML(http.StatusNotFound, "404.html", gin.H{
-			"title": "404 - ページが見つかりません | infoHiroki",
-			"page":  "404",
-		})
-	})
-
-	// サーバー起動
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
-	}
-	r.Run(":" + port)
+	r.GET("/api/tags", listTerms)
+	r.GET("/api/archive", archiveAPI)
+	r.GET("/api/posts/:slug/related", relatedPostsAPI)
+
+	// 管理用API（ADMIN_TOKEN 設定時のみ）
+	admin := r.Group("/api/admin", requireAdmin)
+	admin.GET("/redirects", listRedirects)
+	admin.GET("/previews", listPreviews)
+
+	// 404エラーハンドラー（旧URLは articles/redirects.yml・記事の aliases で転送）
+	r.NoRoute(renderNotFound)
+	return r
 }
 
 // ホームページ
@@ -102,32 +172,101 @@ func homePage(c *gin.Context) {
 		"ogTitle":         "infoHiroki - エンジニアが直接相談対応｜中小企業DX・生成AI支援",
 		"ogDescription":   "技術者が直接ヒアリング・提案。開発からコンサルまでワンストップ。中小企業・スタートアップのDX・生成AI導入を伴走支援",
 		"ogType":          "website",
+		"jsonLD":          jsonLD(siteOrganization()),
 	})
 }
 
 // ブログ一覧
 func blogList(c *gin.Context) {
+	renderBlogList(c, blogListing{
+		path:        "/blog",
+		title:       "ブログ | infoHiroki",
+		description: "infoHirokiのブログ - 生成AI・技術・開発に関する記事を配信中",
+		filter:      postFilter{Tags: c.QueryArray("tag"), Category: c.Query("category")},
+		showTerms:   true,
+	})
+}
+
+// 一覧ページの種類ごとの設定（ブログ一覧・タグ別・カテゴリ別）
+type blogListing struct {
+	path        string
+	title       string
+	heading     string
+	description string
+	filter      postFilter
+	defaultSort string // 並び順の既定値（空なら新しい順）
+	showTerms   bool   // タグクラウドを表示
+}
+
+// 記事一覧を描画（検索・並び替え・ページ送り対応）
+func renderBlogList(c *gin.Context, listing blogListing) {
 	query := c.Query("q")
+	order := normalizeSort(c.Query("sort"), query, listing.defaultSort)
+	page := positiveIntParam(c.Query("page"), 1)
+	snap := store.Snapshot()
 
 	// ファイルベースでのフィルタリング
-	posts := filterPosts(allPosts, query)
+	hits := filterPosts(snap, query, listing.filter)
+	sortHits(hits, order)
+	total := len(hits)
+	hits = pageHits(hits, pageOffset(page, blogPageSize, total), blogPageSize)
+	pages := newPagination(listing.path, c.Request.URL.Query(), page, blogPageSize, total)
+
+	// Markdown・JSONは記事の要約一覧のみ（検索語・ページはURLのまま）
+	setMarkdownAlternate(c, siteBaseURL+c.Request.URL.RequestURI())
+	switch negotiate(c) {
+	case formatMarkdown:
+		c.Data(http.StatusOK, markdownContentType, []byte(blogListMarkdown(listing, hits, pages)))
+		return
+	case formatJSON:
+		c.JSON(http.StatusOK, blogListJSON(listing, hits, pages, query, order))
+		return
+	}
+
+	posts := make([]models.BlogPost, len(hits))
+	snippets := map[string][]search.Snippet{}
+	for i, hit := range hits {
+		posts[i] = hit.Post
+		if query != "" {
+			snippets[hit.Post.Slug] = postSnippets(&hit.Post, query, 1)
+		}
+	}
 
-	c.HTML(http.StatusOK, "blog.html", gin.H{
-		"title":           "ブログ | infoHiroki",
+	data := gin.H{
+		"title":           listing.title,
+		"heading":         listing.heading,
 		"page":            "blog",
 		"posts":           posts,
+		"snippets":        snippets,
 		"query":           query,
-		"metaDescription": "infoHirokiのブログ - 生成AI・技術・開発に関する記事を配信中",
-		"ogTitle":         "ブログ | infoHiroki",
+		"sort":            order,
+		"filter":          listing.filter,
+		"pagination":      pages,
+		"metaDescription": listing.description,
+		"ogTitle":         listing.title,
 		"ogDescription":   "生成AI・DX導入支援の技術ブログ",
 		"ogType":          "website",
-	})
+		"jsonLD":          blogListJSONLD(listing.path, listing.name()),
+	}
+	if listing.showTerms {
+		data["tagCloud"] = buildTagCloud(snap.Tags(time.Now()), "/blog/tags/")
+		data["categories"] = buildTagCloud(snap.Categories(time.Now()), "/blog/categories/")
+		data["archive"] = snap.Archive(time.Now())
+	}
+
+	c.HTML(http.StatusOK, "blog.html", data)
 }
 
 // ブログ記事詳細（拡張子対応）
 func handleBlogPost(c *gin.Context) {
 	slug := c.Param("slug")
 
+	// /blog/2024 は年別アーカイブ
+	if yearPattern.MatchString(slug) {
+		yearArchivePage(c, slug)
+		return
+	}
+
 	// 拡張子をチェック
 	if strings.HasSuffix(slug, ".md") {
 		// .mdの場合、Markdown形式で返す
@@ -138,11 +277,21 @@ func handleBlogPost(c *gin.Context) {
 		slugWithoutExt := strings.TrimSuffix(slug, ".json")
 		showBlogPostJSON(c, slugWithoutExt)
 	} else {
-		// 拡張子なしの場合、HTML形式で返す
-		showBlogPost(c, slug)
+		// 拡張子なしの場合、Acceptヘッダーで形式を選ぶ（既定はHTML）
+		switch negotiate(c) {
+		case formatMarkdown:
+			showBlogPostMarkdown(c, slug)
+		case formatJSON:
+			showBlogPostJSON(c, slug)
+		default:
+			showBlogPost(c, slug)
+		}
 	}
 }
 
+// 見出しがこの数より多い記事に目次を表示
+const tocMinHeadings = 3
+
 // ブログ記事詳細（HTML）
 func showBlogPost(c *gin.Context, slug string) {
 	post := getBlogPostBySlug(c, slug)
@@ -156,17 +305,30 @@ func showBlogPost(c *gin.Context, slug string) {
 		metaDescription = "infoHiroki - 生成AI・DX導入支援の技術ブログ記事"
 	}
 
+	// Markdown版の所在（AIクローラー向け）
+	setMarkdownAlternate(c, postMarkdownURL(post))
+
+	// OGP画像がなければ小さいカード
+	ogImage := postOGImageURL(post)
+	twitterCard := "summary_large_image"
+	if ogImage == "" {
+		twitterCard = "summary"
+	}
+
 	// HTMLコンテンツをそのまま表示
 	c.HTML(http.StatusOK, "blog_detail.html", gin.H{
-		"title":           post.Title + " | infoHiroki",
-		"page":            "blog",
-		"post":            post, // ポインタのまま渡す
-		"metaDescription": metaDescription,
-		"ogTitle":         post.Title + " | infoHiroki",
-		"ogDescription":   metaDescription,
-		"ogType":          "article",
-		"twitterCard":     "summary",
-		"twitterTitle":    post.Title,
+		"title":              post.Title + " | infoHiroki",
+		"page":               "blog",
+		"post":               post, // ポインタのまま渡す
+		"showTOC":            post.TOCCount() > tocMinHeadings,
+		"metaDescription":    metaDescription,
+		"ogTitle":            post.Title + " | infoHiroki",
+		"ogDescription":      metaDescription,
+		"ogType":             "article",
+		"ogImage":            ogImage,
+		"jsonLD":             blogPostJSONLD(post, metaDescription),
+		"twitterCard":        twitterCard,
+		"twitterTitle":       post.Title,
 		"twitterDescription": metaDescription,
 	})
 }
@@ -178,7 +340,7 @@ func showBlogPostMarkdown(c *gin.Context, slug string) {
 		return
 	}
 
-	c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(post.ToMarkdown()))
+	c.Data(http.StatusOK, markdownContentType, []byte(post.ToMarkdown()))
 }
 
 // ブログ記事詳細（JSON）
@@ -192,27 +354,37 @@ func showBlogPostJSON(c *gin.Context, slug string) {
 
 // 共通処理：スラッグでブログ記事を取得（前後記事付き）
 func getBlogPostBySlug(c *gin.Context, slug string) *models.BlogPost {
-	var currentPost *models.BlogPost
-	var currentIndex int = -1
-
-	// 現在の記事を検索
-	for i, post := range allPosts {
-		if post.Slug == slug && post.Published {
-			currentPost = &post
-			currentIndex = i
-			break
-		}
+	// リクエスト中は同じスナップショットを参照する
+	snap := store.Snapshot()
+	now := time.Now()
+
+	// 現在の記事を検索（スナップショットは共有なのでコピーに前後記事を設定する）
+	post, currentIndex, ok := snap.Lookup(slug)
+	if !ok {
+		renderNotFound(c)
+		return nil
 	}
 
-	if currentPost == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "記事が見つかりません"})
-		return nil
+	// 下書き・予約投稿は署名付きプレビューURLでのみ表示
+	if !post.IsVisible(now) {
+		if !verifyPreviewToken(post.Slug, c.Query("preview"), now) {
+			renderNotFound(c)
+			return nil
+		}
+		c.Header("X-Robots-Tag", "noindex, nofollow")
+		c.Header("Cache-Control", "private, no-store")
 	}
+	currentPost := &post
+
+	// 目次・統計はレンダリング結果のキャッシュから設定（記事ごとに初回のみレンダリング）
+	rendered := currentPost.Rendered()
+	currentPost.TOC = rendered.TOC
+	currentPost.Stats = &rendered.Stats
 
-	// 前後記事を設定（日付順で前後を判定）
-	if currentIndex > 0 {
+	// 前後記事を設定（日付順で前後を判定、非公開記事は飛ばす）
+	nextPost, prevPost := snap.Neighbors(currentIndex, now)
+	if nextPost != nil {
 		// 次の記事（新しい記事）
-		nextPost := allPosts[currentIndex-1]
 		currentPost.NextPost = &models.BlogPost{
 			Slug:        nextPost.Slug,
 			Title:       nextPost.Title,
@@ -222,9 +394,8 @@ func getBlogPostBySlug(c *gin.Context, slug string) *models.BlogPost {
 		}
 	}
 
-	if currentIndex < len(allPosts)-1 {
+	if prevPost != nil {
 		// 前の記事（古い記事）
-		prevPost := allPosts[currentIndex+1]
 		currentPost.PrevPost = &models.BlogPost{
 			Slug:        prevPost.Slug,
 			Title:       prevPost.Title,
@@ -234,240 +405,383 @@ func getBlogPostBySlug(c *gin.Context, slug string) *models.BlogPost {
 		}
 	}
 
-	// 関連記事を設定（タイトル類似度ベース）
-	currentPost.RelatedPosts = findRelatedPosts(currentPost, currentIndex, 3)
-
-	return currentPost
-}
-
-// 関連記事を検索する関数（タイトル類似度ベース）
-func findRelatedPosts(currentPost *models.BlogPost, currentIndex int, limit int) []models.BlogPost {
-	type scoredPost struct {
-		post  models.BlogPost
-		score int
-	}
-
-	var scored []scoredPost
-
-	// 現在の記事のキーワードを抽出
-	currentKeywords := extractKeywords(currentPost.Title + " " + currentPost.Description)
-
-	for i, post := range allPosts {
-		// 自分自身はスキップ
-		if i == currentIndex || !post.Published {
-			continue
-		}
-
-		// キーワード一致スコアを計算
-		postKeywords := extractKeywords(post.Title + " " + post.Description)
-		score := calculateSimilarity(currentKeywords, postKeywords)
-
-		if score > 0 {
-			scored = append(scored, scoredPost{
-				post:  models.BlogPost{
-					Slug:        post.Slug,
-					Title:       post.Title,
-					Description: post.Description,
-					Icon:        post.Icon,
-					CreatedDate: post.CreatedDate,
-				},
-				score: score,
-			})
-		}
-	}
-
-	// スコアの高い順にソート
-	for i := 0; i < len(scored)-1; i++ {
-		for j := i + 1; j < len(scored); j++ {
-			if scored[i].score < scored[j].score {
-				scored[i], scored[j] = scored[j], scored[i]
-			}
-		}
+	// シリーズ目次とシリーズ内の前後記事を設定
+	if post.Series != "" {
+		currentPost.SeriesNav = buildSeriesNav(snap.InSeries(post.Series, now), currentPost)
 	}
 
-	// 上位limit件を返却
-	var result []models.BlogPost
-	for i := 0; i < len(scored) && i < limit; i++ {
-		result = append(result, scored[i].post)
-	}
+	// 関連記事を設定（読み込み時に計算済みのTF-IDF類似度ベース）
+	currentPost.RelatedPosts = findRelatedPosts(snap, currentPost.Slug, relatedPostsLimit, now)
 
-	return result
+	return currentPost
 }
 
-// キーワードを抽出（簡易版）
-func extractKeywords(text string) []string {
-	text = strings.ToLower(text)
-	words := strings.FieldsFunc(text, func(r rune) bool {
-		return !((r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || (r >= 'ぁ' && r <= 'ん') || (r >= 'ァ' && r <= 'ヶ') || (r >= '一' && r <= '龯'))
-	})
-
-	// 重要なキーワードのみ抽出（2文字以上）
-	var keywords []string
-	for _, word := range words {
-		if len(word) >= 2 {
-			keywords = append(keywords, word)
-		}
-	}
+// 固定ページ（llms.txt 等の一覧にもこの順で載せる）
+type staticPage struct {
+	Slug        string
+	Name        string // 一覧での表示名
+	Title       string
+	Description string
+}
 
-	return keywords
+var staticPages = []staticPage{
+	{Slug: "services", Name: "サービス", Title: "中小企業DX・生成AI導入支援 | infoHiroki", Description: "中小企業・スタートアップ向けDX・生成AI導入支援。エンジニアが直接ヒアリング・提案。開発からコンサルまでワンストップ対応"},
+	{Slug: "products", Name: "開発製品", Title: "開発製品 | infoHiroki", Description: "業務効率化ツール・生成AI活用システムの開発製品"},
+	{Slug: "results", Name: "導入実績", Title: "導入実績 | infoHiroki", Description: "中小企業での生成AI導入実績 - 議事録80%削減、月15万円コスト削減など"},
+	{Slug: "about", Name: "スキルスタック", Title: "スキルスタック | infoHiroki", Description: "エンジニアプロフィール - Go/Python/生成AI開発の技術スタック"},
+	{Slug: "faq", Name: "よくある質問", Title: "FAQ | infoHiroki", Description: "よくある質問と回答 - infoHirokiサービスについて"},
+	{Slug: "contact", Name: "お問い合わせ", Title: "お問い合わせ | infoHiroki", Description: "infoHirokiへのお問い合わせ・ご相談はこちら"},
 }
 
-// 類似度を計算（共通キーワード数）
-func calculateSimilarity(keywords1 []string, keywords2 []string) int {
-	score := 0
-	for _, k1 := range keywords1 {
-		for _, k2 := range keywords2 {
-			if k1 == k2 {
-				score++
-			}
+func findStaticPage(slug string) staticPage {
+	for _, page := range staticPages {
+		if page.Slug == slug {
+			return page
 		}
 	}
-	return score
+	return staticPage{Slug: slug, Title: "infoHiroki"}
 }
 
 // 固定ページ処理（サービス、製品、実績、等）
 func servicesPage(c *gin.Context) {
-	renderPageWithMeta(c, "services", "中小企業DX・生成AI導入支援 | infoHiroki", "中小企業・スタートアップ向けDX・生成AI導入支援。エンジニアが直接ヒアリング・提案。開発からコンサルまでワンストップ対応")
+	data := pageMeta("services")
+	data["jsonLD"] = jsonLD(siteProfessionalService())
+	renderStaticPage(c, "services", data)
 }
 
 func productsPage(c *gin.Context) {
-	renderPageWithMeta(c, "products", "開発製品 | infoHiroki", "業務効率化ツール・生成AI活用システムの開発製品")
+	renderPageWithMeta(c, "products")
 }
 
 func resultsPage(c *gin.Context) {
-	renderPageWithMeta(c, "results", "導入実績 | infoHiroki", "中小企業での生成AI導入実績 - 議事録80%削減、月15万円コスト削減など")
+	renderPageWithMeta(c, "results")
 }
 
 func aboutPage(c *gin.Context) {
-	renderPageWithMeta(c, "about", "スキルスタック | infoHiroki", "エンジニアプロフィール - Go/Python/生成AI開発の技術スタック")
+	renderPageWithMeta(c, "about")
 }
 
 func faqPage(c *gin.Context) {
-	renderPageWithMeta(c, "faq", "FAQ | infoHiroki", "よくある質問と回答 - infoHirokiサービスについて")
+	data := pageMeta("faq")
+	data["faqItems"] = faqItems
+	data["jsonLD"] = faqJSONLD(faqItems)
+	renderStaticPage(c, "faq", data)
 }
 
 func contactPage(c *gin.Context) {
-	renderPageWithMeta(c, "contact", "お問い合わせ | infoHiroki", "infoHirokiへのお問い合わせ・ご相談はこちら")
+	renderPageWithMeta(c, "contact")
 }
 
 // 固定ページ共通処理（メタデータ付き）
-func renderPageWithMeta(c *gin.Context, slug string, title string, description string) {
+func renderPageWithMeta(c *gin.Context, slug string) {
 	// 固定ページはテンプレートのみで処理
-	c.HTML(http.StatusOK, slug+".html", gin.H{
-		"title":           title,
+	renderStaticPage(c, slug, pageMeta(slug))
+}
+
+// 固定ページのテンプレートデータ（メタデータ）
+func pageMeta(slug string) gin.H {
+	page := findStaticPage(slug)
+	return gin.H{
+		"title":           page.Title,
 		"page":            slug,
-		"metaDescription": description,
-		"ogTitle":         title,
-		"ogDescription":   description,
+		"metaDescription": page.Description,
+		"ogTitle":         page.Title,
+		"ogDescription":   page.Description,
 		"ogType":          "website",
-	})
+	}
 }
 
-
 // ブログ検索API
 func searchBlogPosts(c *gin.Context) {
 	query := c.Query("q")
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-
-	// ファイルベースでの検索
-	posts := filterPosts(allPosts, query)
-	if len(posts) > limit {
-		posts = posts[:limit]
+	order := normalizeSort(c.Query("sort"), query, "")
+	limit := positiveIntParam(c.DefaultQuery("limit", "10"), 10)
+	if limit > searchMaxLimit {
+		limit = searchMaxLimit
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"posts": posts,
-		"total": len(posts),
-		"query": query,
-	})
-}
+	// offset指定を優先し、なければpageから算出（件数の確定後）。不正な offset はエラー
+	offsetParam := c.Query("offset")
+	offsetGiven := offsetParam != ""
+	offset, err := strconv.Atoi(offsetParam)
+	if offsetGiven && (err != nil || offset < 0) {
+		renderError(c, http.StatusBadRequest, "不正なリクエストです", "offset は0以上の整数で指定してください。", nil)
+		return
+	}
 
-// ファイルベースでのフィルタリング関数
-func filterPosts(posts []models.BlogPost, query string) []models.BlogPost {
-	var result []models.BlogPost
+	// 本文（Markdown）は明示的に指定された場合のみ返す
+	includeContent := c.Query("content") == "1" || c.Query("content") == "true"
 
-	for _, post := range posts {
-		if !post.Published {
-			continue
-		}
+	// ファイルベースでの検索（タグはAND条件）
+	filter := postFilter{Tags: c.QueryArray("tag"), Category: c.Query("category")}
+	hits := filterPosts(store.Snapshot(), query, filter)
+	sortHits(hits, order)
+	total := len(hits)
+	if !offsetGiven {
+		offset = pageOffset(positiveIntParam(c.Query("page"), 1), limit, total)
+	}
+	hits = pageHits(hits, offset, limit)
 
-		// 検索クエリフィルタ
+	results := make([]searchResult, len(hits))
+	for i, hit := range hits {
+		post := hit.Post
+		results[i] = newSearchResult(hit)
 		if query != "" {
-			if !strings.Contains(strings.ToLower(post.Title), strings.ToLower(query)) &&
-			   !strings.Contains(strings.ToLower(post.Description), strings.ToLower(query)) {
-				continue
-			}
+			results[i].Snippets = postSnippets(&post, query, 3)
+		}
+		if includeContent {
+			results[i].Content = post.Content
 		}
+	}
 
+	response := gin.H{
+		"posts":  results,
+		"total":  total,
+		"query":  query,
+		"sort":   order,
+		"offset": offset,
+		"limit":  limit,
+	}
+	if offset+len(results) < total {
+		response["next_offset"] = offset + len(results)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// 検索APIのレスポンス項目（本文は既定で省略）
+type searchResult struct {
+	Slug        string           `json:"slug"`
+	Title       string           `json:"title"`
+	Description string           `json:"description"`
+	Icon        string           `json:"icon"`
+	CreatedDate time.Time        `json:"created_date"`
+	Score       float64          `json:"score"`
+	Snippets    []search.Snippet `json:"snippets,omitempty"`
+	Content     string           `json:"content,omitempty"`
+}
 
-		result = append(result, post)
+func newSearchResult(hit content.Hit) searchResult {
+	return searchResult{
+		Slug:        hit.Post.Slug,
+		Title:       hit.Post.Title,
+		Description: hit.Post.Description,
+		Icon:        hit.Post.Icon,
+		CreatedDate: hit.Post.CreatedDate,
+		Score:       hit.Score,
 	}
+}
 
-	// 新しい記事が上に来るように作成日の降順でソート
-	for i := 0; i < len(result)-1; i++ {
-		for j := i + 1; j < len(result); j++ {
-			if result[i].CreatedDate.Before(result[j].CreatedDate) {
-				result[i], result[j] = result[j], result[i]
-			}
+// 検索語周辺の抜粋（本文に一致がなければ説明文から）
+func postSnippets(post *models.BlogPost, query string, max int) []search.Snippet {
+	if snippets := search.Snippets(post.PlainText(), query, max, 40); len(snippets) > 0 {
+		return snippets
+	}
+	return search.Snippets(post.Description, query, max, 40)
+}
+
+// 検索クエリで記事を絞り込み（クエリなしは新しい順、ありは全文検索のスコア順）
+func filterPosts(snap *content.Snapshot, query string, filter postFilter) []content.Hit {
+	now := time.Now()
+
+	var hits []content.Hit
+	if strings.TrimSpace(query) == "" {
+		for _, post := range snap.Visible(now) {
+			hits = append(hits, content.Hit{Post: post})
 		}
+	} else {
+		hits = snap.Search(query, now)
+	}
+
+	if filter.isEmpty() {
+		return hits
 	}
 
+	result := hits[:0]
+	for _, hit := range hits {
+		if filter.matches(&hit.Post) {
+			result = append(result, hit)
+		}
+	}
 	return result
 }
 
 // データ初期化（ファイルベース）
 func initializeData() {
-	// メモリを初期化
-	allPosts = []models.BlogPost{}
-	allPages = []models.Page{}
+	// 固定ページはテンプレートのみで処理するため空で初期化
+	store.ReplacePages([]models.Page{})
 
 	// Markdownファイルの読み込み
-	loadMarkdownFiles()
+	articles.loadRules()
+	articles.loadRedirects()
+	articles.loadAll()
+	articles.publish()
+
+	fmt.Printf("✅ データ初期化完了: %d件の記事を読み込み\n", store.Snapshot().Len())
+}
+
+// articlesディレクトリの記事をファイル単位で保持（差分リロード用）
+type articleSet struct {
+	dir       string
+	rulesPath string // タグ・カテゴリの自動分類ルール
+	mu        sync.Mutex
+	files     map[string]models.BlogPost
+	rules     models.TaxonomyRules
+
+	redirectsPath string // 旧URLのリダイレクト設定
+	redirects     []redirects.Rule
+}
 
-	fmt.Printf("✅ データ初期化完了: %d件の記事を読み込み\n", len(allPosts))
+func newArticleSet(dir string, rulesPath string, redirectsPath string) *articleSet {
+	return &articleSet{dir: dir, rulesPath: rulesPath, redirectsPath: redirectsPath, files: map[string]models.BlogPost{}}
 }
 
-// content/metadata.jsonからブログ記事を読み込み
+// 自動分類ルールを読み込み（読み込み失敗時は直前のルールを維持）
+func (a *articleSet) loadRules() {
+	rules, err := models.LoadTaxonomyRules(a.rulesPath)
+	if err != nil {
+		fmt.Printf("⚠️ 分類ルールの読み込みエラー: %v\n", err)
+		return
+	}
+
+	a.mu.Lock()
+	a.rules = rules
+	a.mu.Unlock()
+	fmt.Printf("🏷️ 分類ルールを読み込み: %d件\n", len(rules.Rules))
+}
+
+// リダイレクト設定を読み込み（読み込み失敗時は直前の設定を維持）
+func (a *articleSet) loadRedirects() {
+	rules, err := redirects.LoadFile(a.redirectsPath)
+	if err != nil {
+		fmt.Printf("⚠️ リダイレクト設定の読み込みエラー: %v\n", err)
+		return
+	}
+
+	a.mu.Lock()
+	a.redirects = rules
+	a.mu.Unlock()
+}
 
 // articlesディレクトリから記事ファイルを読み込み（Markdown形式）
-func loadMarkdownFiles() {
+func (a *articleSet) loadAll() {
 	fmt.Println("📝 記事ファイルを読み込み中...")
 
-	postsDir := "articles"
-	if _, err := os.Stat(postsDir); os.IsNotExist(err) {
+	files := map[string]models.BlogPost{}
+
+	if _, err := os.Stat(a.dir); os.IsNotExist(err) {
 		fmt.Println("postsディレクトリが存在しません")
-		return
-	}
+	} else {
+		err := filepath.Walk(a.dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
 
-	err := filepath.Walk(postsDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
+			if !isArticleFile(path) {
+				return nil
+			}
 
-		ext := filepath.Ext(path)
-		if ext != ".md" && ext != ".html" {
+			fmt.Printf("処理中: %s\n", path)
+			post, err := loadMarkdownFile(path)
+			if err != nil {
+				return err
+			}
+			files[path] = post
 			return nil
+		})
+
+		if err != nil {
+			fmt.Printf("Markdownファイル読み込みエラー: %v\n", err)
 		}
+	}
 
-		// HTMLファイルはmetadata.jsonで既に処理済みなのでスキップ
-		if ext == ".html" {
-			return nil
+	a.mu.Lock()
+	a.files = files
+	a.mu.Unlock()
+}
+
+// 変更のあったファイルだけを読み直す（存在しなければ削除扱い）
+func (a *articleSet) reload(paths []string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, path := range paths {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			if _, ok := a.files[path]; ok {
+				delete(a.files, path)
+				fmt.Printf("🗑️ 記事を削除: %s\n", path)
+			}
+			continue
 		}
 
-		fmt.Printf("処理中: %s\n", path)
-		return loadMarkdownFile(path)
-	})
+		post, err := loadMarkdownFile(path)
+		if err != nil {
+			// 読み込みに失敗した場合は直前の内容を維持
+			fmt.Printf("Markdownファイル読み込みエラー: %v\n", err)
+			continue
+		}
+		a.files[path] = post
+		fmt.Printf("🔄 記事を再読み込み: %s\n", path)
+	}
+}
 
-	if err != nil {
-		fmt.Printf("Markdownファイル読み込みエラー: %v\n", err)
+// 記事一覧を組み立ててスナップショットを差し替え
+func (a *articleSet) publish() {
+	a.mu.Lock()
+	paths := make([]string, 0, len(a.files))
+	for path := range a.files {
+		paths = append(paths, path)
 	}
+	sort.Strings(paths)
+
+	posts := make([]models.BlogPost, 0, len(paths))
+	seen := map[string]string{}
+	for _, path := range paths {
+		post := a.files[path]
+		a.rules.Apply(&post)
+		if first, ok := seen[post.Slug]; ok {
+			// 既に存在する場合はスキップ
+			fmt.Printf("⚠️ スラッグが重複しています: %s (%s, %s)\n", post.Slug, first, path)
+			continue
+		}
+		seen[post.Slug] = path
+		posts = append(posts, post)
+	}
+	redirectRules := a.redirects
+	a.mu.Unlock()
+
+	// 並び順とインデックスはストア側で構築
+	store.ReplacePosts(posts)
+	logPreviewURLs(posts)
+
+	// 記事の aliases が変わるので作り直す（既存記事との重なりは新しいスナップショットで検証）
+	rebuildRedirects(redirectRules, posts)
+}
+
+// リダイレクト設定だけを反映（記事のスナップショットはそのまま）
+func (a *articleSet) publishRedirects() {
+	a.mu.Lock()
+	redirectRules := a.redirects
+	a.mu.Unlock()
+	rebuildRedirects(redirectRules, store.Snapshot().Posts())
+}
+
+// 記事として扱うファイルか判定
+func isArticleFile(path string) bool {
+	// HTMLファイルはmetadata.jsonで既に処理済みなのでスキップ
+	return filepath.Ext(path) == ".md"
 }
 
 // 個別のMarkdownファイルを読み込み
-func loadMarkdownFile(filePath string) error {
+func loadMarkdownFile(filePath string) (models.BlogPost, error) {
 	content, err := os.ReadFile(filePath)
 	if err != nil {
-		return err
+		return models.BlogPost{}, err
+	}
+
+	// フロントマターを分離（あれば推測より優先）
+	frontMatter, body, err := models.ParseFrontMatter(string(content))
+	if err != nil {
+		fmt.Printf("⚠️ フロントマター解析エラー: %s: %v\n", filePath, err)
 	}
 
 	// ファイル名からスラッグを生成
@@ -475,48 +789,63 @@ func loadMarkdownFile(filePath string) error {
 	ext := filepath.Ext(fileName)
 	slug := strings.TrimSuffix(fileName, ext)
 
-	// 既存記事の確認
-	for _, existing := range allPosts {
-		if existing.Slug == slug {
-			// 既に存在する場合はスキップ
-			return nil
-		}
+	// ファイル名から日付を抽出（スラッグの上書き前のファイル名で）
+	createdDate := models.DateFromFileName(slug)
+	if frontMatter.Slug != "" {
+		slug = frontMatter.Slug
 	}
 
-	// ファイル名から日付を抽出
-	var createdDate time.Time
-	if len(slug) >= 10 && slug[4] == '-' && slug[7] == '-' {
-		dateStr := slug[:10]
-		parsedDate, err := time.Parse("2006-01-02", dateStr)
-		if err == nil {
-			createdDate = parsedDate
-		}
+	if date := frontMatter.ParsedDate(); !date.IsZero() {
+		createdDate = date
 	}
 
 	if createdDate.IsZero() {
 		createdDate = time.Now()
 	}
 
-	// Markdownファイルからメタデータを動的に抽出
-	title := extractTitleFromMarkdown(string(content))
-	description := extractDescriptionFromMarkdown(string(content))
-	icon := extractIconFromTitle(title)
+	// フロントマターにない項目はMarkdownから動的に抽出
+	title := frontMatter.Title
+	if title == "" {
+		title = extractTitleFromMarkdown(body)
+	}
+	description := frontMatter.Description
+	if description == "" {
+		description = extractDescriptionFromMarkdown(body)
+	}
+	icon := frontMatter.Icon
+	if icon == "" {
+		icon = extractIconFromTitle(title)
+	}
+
+	updatedAt := frontMatter.ParsedUpdated()
+	if updatedAt.IsZero() {
+		updatedAt = createdDate
+	}
 
 	blogPost := models.BlogPost{
 		Slug:         slug,
 		Title:        title,
-		Content:      string(content),
+		Content:      body,
 		MarkdownPath: filePath,
 		CreatedDate:  createdDate,
-		Published:    true,
+		UpdatedAt:    updatedAt,
+		Published:    !frontMatter.Draft,
 		Description:  description,
 		Icon:         icon,
+		Tags:         models.NormalizeTags(frontMatter.Tags),
+		Category:     models.NormalizeTag(frontMatter.Category),
+		Series:       models.NormalizeTag(frontMatter.Series),
+		SeriesTitle:  frontMatter.SeriesTitle,
+		SeriesPart:   frontMatter.SeriesPart,
+		Aliases:      frontMatter.Aliases,
+		Canonical:    frontMatter.Canonical,
+		OGImage:      frontMatter.OGImage,
 	}
-
-	allPosts = append(allPosts, blogPost)
+	// ファイルを読み直すたびに新しいキャッシュになる
+	blogPost.EnableRenderCache()
 
 	fmt.Printf("✅ Markdown記事を追加: %s\n", slug)
-	return nil
+	return blogPost, nil
 }
 
 // Markdownファイルからタイトルを抽出
@@ -534,6 +863,17 @@ func extractTitleFromMarkdown(content string) string {
 	return "タイトル未設定"
 }
 
+// 先頭 n バイト以内に切り詰める（マルチバイト文字の途中では切らない）
+func truncateBytes(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
 // Markdownファイルから説明文を抽出
 func extractDescriptionFromMarkdown(content string) string {
 	lines := strings.Split(content, "\n")
@@ -566,14 +906,14 @@ func extractDescriptionFromMarkdown(content string) string {
 			if len(sentences) > 0 && sentences[0] != "" {
 				firstSentence := sentences[0]
 				if len(firstSentence) > 150 {
-					return firstSentence[:150] + "..."
+					return truncateBytes(firstSentence, 150) + "..."
 				}
 				return firstSentence + "。"
 			}
 
 			// 句点がない場合は最初の150文字
 			if len(cleanText) > 150 {
-				return cleanText[:150] + "..."
+				return truncateBytes(cleanText, 150) + "..."
 			}
 			return cleanText
 		}
@@ -585,8 +925,8 @@ func extractDescriptionFromMarkdown(content string) string {
 
 		// 空行や見出し、画像、テーブル記号はスキップ
 		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "![") ||
-		   strings.HasPrefix(line, "---") || strings.HasPrefix(line, "|") ||
-		   strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ") {
+			strings.HasPrefix(line, "---") || strings.HasPrefix(line, "|") ||
+			strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ") {
 			continue
 		}
 
@@ -595,7 +935,7 @@ func extractDescriptionFromMarkdown(content string) string {
 			// **で囲まれた部分を削除
 			cleanText := strings.ReplaceAll(line, "**", "")
 			if len(cleanText) > 150 {
-				return cleanText[:150] + "..."
+				return truncateBytes(cleanText, 150) + "..."
 			}
 			return cleanText
 		}
@@ -641,7 +981,7 @@ func extractIconFromTitle(title string) string {
 
 // sitemap.xml 生成
 func sitemapXML(c *gin.Context) {
-	baseURL := "https://infohiroki.com"
+	baseURL := siteBaseURL
 
 	// XML開始
 	xml := `<?xml version="1.0" encoding="UTF-8"?>
@@ -673,8 +1013,28 @@ func sitemapXML(c *gin.Context) {
 `, baseURL, page.loc, page.priority, page.changefreq)
 	}
 
+	snap := store.Snapshot()
+
+	// タグ・カテゴリ一覧ページ
+	for _, term := range snap.Categories(time.Now()) {
+		xml += fmt.Sprintf(`  <url>
+    <loc>%s/blog/categories/%s</loc>
+    <priority>0.5</priority>
+    <changefreq>weekly</changefreq>
+  </url>
+`, baseURL, template.HTMLEscapeString(url.PathEscape(term.Name)))
+	}
+	for _, term := range snap.Tags(time.Now()) {
+		xml += fmt.Sprintf(`  <url>
+    <loc>%s/blog/tags/%s</loc>
+    <priority>0.4</priority>
+    <changefreq>weekly</changefreq>
+  </url>
+`, baseURL, template.HTMLEscapeString(url.PathEscape(term.Name)))
+	}
+
 	// ブログ記事を動的追加
-	for _, post := range allPosts {
+	for _, post := range snap.Visible(time.Now()) {
 		if post.Published {
 			xml += fmt.Sprintf(`  <url>
     <loc>%s/blog/%s</loc>
@@ -682,7 +1042,7 @@ func sitemapXML(c *gin.Context) {
     <priority>0.6</priority>
     <changefreq>monthly</changefreq>
   </url>
-`, baseURL, post.Slug, post.CreatedDate.Format("2006-01-02"))
+`, baseURL, template.HTMLEscapeString(url.PathEscape(post.Slug)), postUpdated(&post).Format("2006-01-02"))
 		}
 	}
 
@@ -692,4 +1052,3 @@ func sitemapXML(c *gin.Context) {
 	c.Header("Content-Type", "application/xml; charset=utf-8")
 	c.String(http.StatusOK, xml)
 }
-
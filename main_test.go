@@ -0,0 +1,132 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"infohiroki-go/src/models"
+)
+
+// テスト用に記事を入れ替える（テスト終了時に空に戻す）
+func seedPosts(t *testing.T, posts ...models.BlogPost) {
+	t.Helper()
+	store.ReplacePosts(posts)
+	t.Cleanup(func() { store.ReplacePosts(nil) })
+}
+
+// ルーター経由でリクエストを処理（テンプレートも実際に描画）
+func serve(t *testing.T, req *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	newRouter().ServeHTTP(w, req)
+	return w
+}
+
+func get(t *testing.T, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	return serve(t, httptest.NewRequest(http.MethodGet, target, nil))
+}
+
+func TestLoadMarkdownFileDateWithSlug(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "2024-06-01-foo.md")
+	if err := os.WriteFile(path, []byte("---\nslug: foo\n---\n# Foo\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	post, err := loadMarkdownFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if post.Slug != "foo" {
+		t.Errorf("slug = %q, want foo", post.Slug)
+	}
+	// スラッグを上書きしても日付はファイル名から取る
+	if want := time.Date(2024, 6, 1, 0, 0, 0, 0, models.PostLocation); !post.CreatedDate.Equal(want) {
+		t.Errorf("created = %v, want %v", post.CreatedDate, want)
+	}
+}
+
+// ウォッチャーと同じ reload → publish で、変更・削除したファイルだけがスナップショットに反映される
+func TestArticleSetReloadSingleFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, body string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	a := write("2024-01-10-a.md", "---\ntags: [go]\nseries: intro\nseries_part: 1\n---\n# A\n")
+	b := write("2024-02-10-b.md", "---\ntags: [go]\nseries: intro\nseries_part: 2\n---\n# B\n")
+	write("2024-03-10-c.md", "---\ntags: [web]\n---\n# C\n")
+
+	set := newArticleSet(dir, filepath.Join(dir, "none.yml"), filepath.Join(dir, "none.yml"))
+	set.loadAll()
+	set.publish()
+	t.Cleanup(func() { store.ReplacePosts(nil) })
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	names := func(posts []models.BlogPost) string {
+		var result []string
+		for _, post := range posts {
+			result = append(result, post.Slug)
+		}
+		return strings.Join(result, ",")
+	}
+
+	// b のタグとシリーズを変えて再読み込み
+	write("2024-02-10-b.md", "---\ntags: [rust]\n---\n# B\n")
+	set.reload([]string{b})
+	set.publish()
+	snap := store.Snapshot()
+	if got := names(snap.WithTag("go", now)); got != "2024-01-10-a" {
+		t.Errorf("tag go = %q", got)
+	}
+	if got := names(snap.WithTag("rust", now)); got != "2024-02-10-b" {
+		t.Errorf("tag rust = %q", got)
+	}
+	if got := names(snap.InSeries("intro", now)); got != "2024-01-10-a" {
+		t.Errorf("series intro = %q", got)
+	}
+
+	// a を削除
+	if err := os.Remove(a); err != nil {
+		t.Fatal(err)
+	}
+	set.reload([]string{a})
+	set.publish()
+	snap = store.Snapshot()
+	if _, _, ok := snap.Lookup("2024-01-10-a"); ok {
+		t.Error("removed article still found by slug")
+	}
+	if got := names(snap.InMonth(2024, time.January, now)); got != "" {
+		t.Errorf("2024-01 = %q, want none", got)
+	}
+	if got := names(snap.Visible(now)); got != "2024-03-10-c,2024-02-10-b" {
+		t.Errorf("visible = %q", got)
+	}
+}
+
+// 見出しが tocMinHeadings より多い記事だけ目次を表示
+func TestPostPageTOC(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	seedPosts(t,
+		models.BlogPost{Slug: "short", Title: "短い記事", Published: true, CreatedDate: created, Content: "## a\n\n## b\n\n## c\n"},
+		models.BlogPost{Slug: "long", Title: "長い記事", Published: true, CreatedDate: created, Content: "## a\n\n## b\n\n### b1\n\n## c\n"},
+	)
+
+	if body := get(t, "/blog/short").Body.String(); strings.Contains(body, `class="post-toc"`) {
+		t.Error("TOC shown for 3 headings")
+	}
+	body := get(t, "/blog/long").Body.String()
+	if !strings.Contains(body, `class="post-toc"`) || !strings.Contains(body, `<li class="post-toc-item post-toc-level-3"><a href="#b1">b1</a>`) {
+		t.Errorf("TOC missing or not nested:\n%s", body)
+	}
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"infohiroki-go/src/content"
+)
+
+// 応答形式（Accept ヘッダーまたは .md / .json の拡張子で選ぶ）
+const (
+	formatHTML     = "html"
+	formatMarkdown = "markdown"
+	formatJSON     = "json"
+)
+
+const markdownContentType = "text/markdown; charset=utf-8"
+
+// 提供できるメディアタイプ（同じ評価なら先頭を優先）
+var formatOffers = []struct {
+	mediaType string
+	format    string
+}{
+	{"text/html", formatHTML},
+	{"text/markdown", formatMarkdown},
+	{"application/json", formatJSON},
+}
+
+// Accept ヘッダーで応答形式を選ぶ。同じURLで形式が変わるので Vary: Accept を付ける
+func negotiate(c *gin.Context) string {
+	c.Header("Vary", "Accept")
+	return negotiateFormat(c.GetHeader("Accept"))
+}
+
+// Markdown版の所在を Link ヘッダーで示す（AIクローラー向け。一覧・固定ページは同じURLを Accept で取得）
+func setMarkdownAlternate(c *gin.Context, href string) {
+	c.Header("Link", "<"+href+`>; rel="alternate"; type="text/markdown"`)
+}
+
+// Accept ヘッダーの q 値で最も優先度の高い形式を返す。
+// 指定なし・該当なしの場合は HTML（ブラウザ以外でも従来どおりの応答）
+func negotiateFormat(accept string) string {
+	if strings.TrimSpace(accept) == "" {
+		return formatHTML
+	}
+	ranges := parseAccept(accept)
+
+	best, bestQ, bestSpecificity := formatHTML, 0.0, -1
+	for _, offer := range formatOffers {
+		// 提供するタイプに一致する範囲のうち最も具体的なものの q 値を使う
+		q, specificity := 0.0, -1
+		for _, r := range ranges {
+			if s := r.matches(offer.mediaType); s > specificity {
+				q, specificity = r.q, s
+			}
+		}
+		if specificity < 0 || q <= 0 {
+			continue
+		}
+		if q > bestQ || (q == bestQ && specificity > bestSpecificity) {
+			best, bestQ, bestSpecificity = offer.format, q, specificity
+		}
+	}
+	return best
+}
+
+// acceptRange is one media range of an Accept header
+type acceptRange struct {
+	mediaType string // type/subtype（小文字）
+	q         float64
+}
+
+func parseAccept(accept string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(accept, ",") {
+		fields := strings.Split(part, ";")
+		r := acceptRange{mediaType: strings.ToLower(strings.TrimSpace(fields[0])), q: 1}
+		if r.mediaType == "" {
+			continue
+		}
+		for _, param := range fields[1:] {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(strings.TrimSpace(key), "q") {
+				if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					r.q = q
+				}
+			}
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges
+}
+
+// 一致の具体性（完全一致 2、type/* 1、*/* 0、不一致 -1）
+func (r acceptRange) matches(mediaType string) int {
+	switch {
+	case r.mediaType == mediaType:
+		return 2
+	case r.mediaType == "*/*":
+		return 0
+	case strings.HasSuffix(r.mediaType, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(r.mediaType, "*")):
+		return 1
+	}
+	return -1
+}
+
+// 固定ページを Accept に応じた形式で返す（Markdown・JSONはページの概要）
+func renderStaticPage(c *gin.Context, slug string, data gin.H) {
+	setMarkdownAlternate(c, siteBaseURL+"/"+slug)
+	switch negotiate(c) {
+	case formatMarkdown:
+		c.Data(http.StatusOK, markdownContentType, []byte(staticPageMarkdown(findStaticPage(slug))))
+	case formatJSON:
+		c.JSON(http.StatusOK, staticPageJSON(findStaticPage(slug)))
+	default:
+		c.HTML(http.StatusOK, slug+".html", data)
+	}
+}
+
+func staticPageMarkdown(page staticPage) string {
+	var b strings.Builder
+	b.WriteString("# " + page.Name + "\n\n")
+	b.WriteString(page.Description + "\n\n")
+	b.WriteString("URL: " + siteBaseURL + "/" + page.Slug + "\n")
+	if page.Slug == "faq" {
+		for _, item := range faqItems {
+			b.WriteString("\n## " + item.Question + "\n\n")
+			b.WriteString(faqAnswerText(item) + "\n")
+		}
+	}
+	return b.String()
+}
+
+func staticPageJSON(page staticPage) gin.H {
+	response := gin.H{
+		"slug":        page.Slug,
+		"name":        page.Name,
+		"title":       page.Title,
+		"description": page.Description,
+		"url":         siteBaseURL + "/" + page.Slug,
+	}
+	if page.Slug == "faq" {
+		questions := make([]gin.H, len(faqItems))
+		for i, item := range faqItems {
+			questions[i] = gin.H{"question": item.Question, "answer": faqAnswerText(item)}
+		}
+		response["faq"] = questions
+	}
+	return response
+}
+
+// 一覧ページの見出し（タイトルからサイト名を除いたもの）
+func (l blogListing) name() string {
+	return strings.TrimSuffix(l.title, " | infoHiroki")
+}
+
+// 記事一覧のMarkdown（各記事はMarkdown版へのリンク）
+func blogListMarkdown(listing blogListing, hits []content.Hit, pages pagination) string {
+	var b strings.Builder
+	b.WriteString("# " + listing.name() + "\n\n")
+	if listing.description != "" {
+		b.WriteString(listing.description + "\n\n")
+	}
+	fmt.Fprintf(&b, "%d件（%d / %dページ）\n\n", pages.Total, pages.Page, pages.TotalPages)
+	for i := range hits {
+		post := &hits[i].Post
+		b.WriteString(llmsLink(post.Title+"（"+post.CreatedDate.Format("2006-01-02")+"）", postMarkdownURL(post), post.Description))
+	}
+	if pages.PrevURL != "" {
+		b.WriteString("\n前のページ: " + siteBaseURL + pages.PrevURL + "\n")
+	}
+	if pages.NextURL != "" {
+		b.WriteString("\n次のページ: " + siteBaseURL + pages.NextURL + "\n")
+	}
+	return b.String()
+}
+
+// 記事一覧のJSON（検索APIと同じ項目、ページ送りはURLで返す）
+func blogListJSON(listing blogListing, hits []content.Hit, pages pagination, query string, order string) gin.H {
+	results := make([]searchResult, len(hits))
+	for i, hit := range hits {
+		results[i] = newSearchResult(hit)
+	}
+	response := gin.H{
+		"title":       listing.name(),
+		"description": listing.description,
+		"url":         siteBaseURL + listing.path,
+		"posts":       results,
+		"total":       pages.Total,
+		"page":        pages.Page,
+		"total_pages": pages.TotalPages,
+		"query":       query,
+		"sort":        order,
+	}
+	if pages.PrevURL != "" {
+		response["prev"] = siteBaseURL + pages.PrevURL
+	}
+	if pages.NextURL != "" {
+		response["next"] = siteBaseURL + pages.NextURL
+	}
+	return response
+}
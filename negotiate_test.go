@@ -0,0 +1,108 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"infohiroki-go/src/models"
+)
+
+func TestNegotiateFormat(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", formatHTML},
+		{"*/*", formatHTML},
+		{"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", formatHTML},
+		{"text/markdown", formatMarkdown},
+		{"Text/Markdown", formatMarkdown},
+		{"application/json", formatJSON},
+		{"text/html;q=0.5, text/markdown", formatMarkdown},
+		{"text/markdown;q=0.5, application/json;q=0.9", formatJSON},
+		// 同じ q なら具体的な指定、さらに同じなら HTML を優先
+		{"text/*, application/json;q=1", formatJSON},
+		{"text/*", formatHTML},
+		{"text/markdown, text/html", formatHTML},
+		// q=0 は拒否、該当なしは HTML
+		{"text/html;q=0, */*", formatMarkdown},
+		{"image/png", formatHTML},
+		{"text/markdown;q=abc", formatMarkdown},
+		{" , ;q=1", formatHTML},
+	}
+	for _, tt := range tests {
+		if got := negotiateFormat(tt.accept); got != tt.want {
+			t.Errorf("negotiateFormat(%q) = %s, want %s", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func getAccept(t *testing.T, target, accept string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	return serve(t, req)
+}
+
+func TestBlogPostNegotiation(t *testing.T) {
+	seedPosts(t, models.BlogPost{Slug: "hello", Title: "こんにちは", Content: "本文です", Published: true, CreatedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)})
+
+	tests := []struct {
+		target      string
+		accept      string
+		contentType string
+		body        string
+		vary        bool
+	}{
+		{"/blog/hello", "", "text/html", "<html", true},
+		{"/blog/hello", "text/html,*/*;q=0.8", "text/html", "<html", true},
+		{"/blog/hello", "text/markdown", "text/markdown", "# こんにちは", true},
+		{"/blog/hello", "application/json", "application/json", `"slug":"hello"`, true},
+		// 拡張子付きは Accept に関係なく固定（Vary なし）
+		{"/blog/hello.md", "text/html", "text/markdown", "# こんにちは", false},
+		{"/blog/hello.json", "text/markdown", "application/json", `"slug":"hello"`, false},
+		{"/blog", "text/markdown", "text/markdown", "こんにちは", true},
+		{"/blog", "application/json", "application/json", "hello", true},
+		{"/about", "text/markdown", "text/markdown", "# スキルスタック", true},
+		{"/faq", "application/json", "application/json", `"slug":"faq"`, true},
+	}
+	for _, tt := range tests {
+		w := getAccept(t, tt.target, tt.accept)
+		if w.Code != http.StatusOK {
+			t.Errorf("%s (%s): status %d", tt.target, tt.accept, w.Code)
+			continue
+		}
+		if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, tt.contentType) {
+			t.Errorf("%s (%s): content type %q, want %s", tt.target, tt.accept, ct, tt.contentType)
+		}
+		if !strings.Contains(w.Body.String(), tt.body) {
+			t.Errorf("%s (%s): body missing %q", tt.target, tt.accept, tt.body)
+		}
+		if got := w.Header().Get("Vary") == "Accept"; got != tt.vary {
+			t.Errorf("%s (%s): Vary %q", tt.target, tt.accept, w.Header().Get("Vary"))
+		}
+	}
+
+	// HTML版にも Markdown版の所在を示す
+	if link := getAccept(t, "/blog/hello", "").Header().Get("Link"); link != "<"+siteBaseURL+`/blog/hello.md>; rel="alternate"; type="text/markdown"` {
+		t.Errorf("Link = %q", link)
+	}
+}
+
+func TestNotFoundNegotiation(t *testing.T) {
+	seedPosts(t)
+
+	w := getAccept(t, "/blog/missing", "application/json")
+	if w.Code != http.StatusNotFound || !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		t.Errorf("JSON 404: status %d, content type %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	w = getAccept(t, "/blog/missing", "text/html")
+	if w.Code != http.StatusNotFound || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") {
+		t.Errorf("HTML 404: status %d, content type %q", w.Code, w.Header().Get("Content-Type"))
+	}
+}
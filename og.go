@@ -0,0 +1,81 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"infohiroki-go/src/models"
+	"infohiroki-go/src/ogimage"
+)
+
+// 記事のOGP画像キャッシュ（cache.og_dir に保存）
+var ogCache *ogimage.Cache
+
+// 記事のOGP画像URL（フロントマターの指定があればそちらを優先。生成しない設定で指定もなければ空）
+func postOGImageURL(post *models.BlogPost) string {
+	if post.OGImage != "" {
+		return absoluteURL(post.OGImage)
+	}
+	if !siteConfig.Features.OGImages {
+		return ""
+	}
+	return siteBaseURL + "/og/" + post.Slug + ".png"
+}
+
+// 記事のOGP画像の内容
+func postOGCard(post *models.BlogPost) ogimage.Card {
+	card := ogimage.Card{
+		Title: post.Title,
+		Label: post.Category,
+		Site:  "infoHiroki",
+		Date:  post.CreatedDate,
+	}
+	if post.IsIconURL() {
+		card.IconFile = ogIconFile(post.Icon)
+	} else {
+		card.Icon = post.Icon
+	}
+	return card
+}
+
+// OGP画像のアイコンに使える拡張子（ogimage が読める形式）
+var ogIconExts = map[string]bool{".png": true, ".jpg": true, ".jpeg": true, ".gif": true}
+
+// アイコンのパスを static/images 配下の画像ファイルに変換（外部URL・範囲外・画像以外は空）
+func ogIconFile(icon string) string {
+	clean := path.Clean(icon)
+	if !strings.HasPrefix(clean, "/images/") || !ogIconExts[strings.ToLower(path.Ext(clean))] {
+		return ""
+	}
+	return filepath.Join("static", filepath.FromSlash(clean))
+}
+
+// 記事のOGP画像（/og/:slug.png）
+func ogImage(c *gin.Context) {
+	slug, ok := strings.CutSuffix(c.Param("file"), ".png")
+	if !ok {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	now := time.Now()
+	post, _, found := store.Snapshot().Lookup(slug)
+	if !found || (!post.IsVisible(now) && !verifyPreviewToken(post.Slug, c.Query("preview"), now)) {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	path, err := ogCache.File(post.Slug, postOGCard(&post))
+	if err != nil {
+		log.Printf("⚠️ OGP画像を生成できません: %s: %v", slug, err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	c.Header("Cache-Control", cacheControl())
+	c.File(path)
+}
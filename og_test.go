@@ -0,0 +1,32 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestOGIconFile(t *testing.T) {
+	tests := []struct {
+		icon string
+		want string
+	}{
+		{"/images/icons/go.png", filepath.Join("static", "images", "icons", "go.png")},
+		{"/images/icons/go.JPG", filepath.Join("static", "images", "icons", "go.JPG")},
+		{"/images/./icons//go.gif", filepath.Join("static", "images", "icons", "go.gif")},
+		// static/images の外
+		{"/images/../../main.go", ""},
+		{"/images/../css/style.png", ""},
+		{"/css/logo.png", ""},
+		{"./images/go.png", ""},
+		{"https://example.com/images/go.png", ""},
+		// 画像以外・読めない形式
+		{"/images/logo.svg", ""},
+		{"/images/notes.txt", ""},
+		{"/images/icons", ""},
+	}
+	for _, tt := range tests {
+		if got := ogIconFile(tt.icon); got != tt.want {
+			t.Errorf("ogIconFile(%q) = %q, want %q", tt.icon, got, tt.want)
+		}
+	}
+}
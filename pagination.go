@@ -0,0 +1,149 @@
+package main
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"infohiroki-go/src/content"
+)
+
+// ブログ一覧の1ページあたりの記事数
+const blogPageSize = 20
+
+// 検索APIの取得件数の上限
+const searchMaxLimit = 100
+
+// 並び順
+const (
+	sortNewest    = "newest"
+	sortOldest    = "oldest"
+	sortRelevance = "relevance"
+	sortTitle     = "title"
+	sortSeries    = "series"
+)
+
+// 並び順を正規化（未指定・不正値はクエリの有無で既定値を決める）
+func normalizeSort(value string, query string, fallback string) string {
+	switch value {
+	case sortNewest, sortOldest, sortTitle:
+		return value
+	case sortSeries:
+		if fallback == sortSeries {
+			return value
+		}
+	case sortRelevance:
+		if strings.TrimSpace(query) != "" {
+			return value
+		}
+	}
+	if strings.TrimSpace(query) != "" {
+		return sortRelevance
+	}
+	if fallback != "" {
+		return fallback
+	}
+	return sortNewest
+}
+
+// 検索結果を並べ替え（relevanceは検索スコア順のまま、seriesはシリーズナビと同じ順）
+func sortHits(hits []content.Hit, order string) {
+	switch order {
+	case sortNewest:
+		sort.SliceStable(hits, func(i, j int) bool {
+			return hits[i].Post.CreatedDate.After(hits[j].Post.CreatedDate)
+		})
+	case sortOldest:
+		sort.SliceStable(hits, func(i, j int) bool {
+			return hits[i].Post.CreatedDate.Before(hits[j].Post.CreatedDate)
+		})
+	case sortTitle:
+		sort.SliceStable(hits, func(i, j int) bool {
+			return strings.ToLower(hits[i].Post.Title) < strings.ToLower(hits[j].Post.Title)
+		})
+	case sortSeries:
+		sort.SliceStable(hits, func(i, j int) bool {
+			return hits[i].Post.SeriesBefore(&hits[j].Post)
+		})
+	}
+}
+
+// 件数から範囲を切り出し（範囲外・負の位置は空）
+func pageHits(hits []content.Hit, offset int, limit int) []content.Hit {
+	if offset < 0 || offset >= len(hits) || limit < 1 {
+		return []content.Hit{}
+	}
+	end := len(hits)
+	if limit < end-offset {
+		end = offset + limit
+	}
+	return hits[offset:end]
+}
+
+// ページ番号から開始位置を算出（最終ページより先は件数を返す。巨大なページ番号でも桁あふれしない）
+func pageOffset(page int, pageSize int, total int) int {
+	if page < 1 {
+		page = 1
+	}
+	if page-1 > total/pageSize {
+		return total
+	}
+	return (page - 1) * pageSize
+}
+
+// 正の整数パラメータを取得（不正値は既定値）
+func positiveIntParam(value string, def int) int {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return def
+	}
+	return n
+}
+
+// ブログ一覧のページ送り情報
+type pagination struct {
+	Page       int
+	TotalPages int
+	Total      int
+	PrevURL    string
+	NextURL    string
+}
+
+// ページ送り情報を作成（リンクには検索語・並び順を引き継ぐ）
+func newPagination(path string, params url.Values, page int, pageSize int, total int) pagination {
+	p := pagination{Page: page, Total: total, TotalPages: (total + pageSize - 1) / pageSize}
+	if p.TotalPages < 1 {
+		p.TotalPages = 1
+	}
+
+	pageURL := func(n int) string {
+		values := url.Values{}
+		for key, vals := range params {
+			// 空のパラメータ（q= など）は引き継がない
+			if len(vals) > 0 && vals[0] != "" {
+				values[key] = vals
+			}
+		}
+		values.Del("page")
+		if n > 1 {
+			values.Set("page", strconv.Itoa(n))
+		}
+		if encoded := values.Encode(); encoded != "" {
+			return path + "?" + encoded
+		}
+		return path
+	}
+
+	if page > 1 {
+		prev := page - 1
+		if prev > p.TotalPages {
+			prev = p.TotalPages
+		}
+		p.PrevURL = pageURL(prev)
+	}
+	if page < p.TotalPages {
+		p.NextURL = pageURL(page + 1)
+	}
+	return p
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"infohiroki-go/src/content"
+	"infohiroki-go/src/models"
+)
+
+func testHits(n int) []content.Hit {
+	hits := make([]content.Hit, n)
+	for i := range hits {
+		hits[i] = content.Hit{Post: models.BlogPost{Slug: "post-" + strconv.Itoa(i)}}
+	}
+	return hits
+}
+
+func TestPageOffset(t *testing.T) {
+	tests := []struct {
+		page, pageSize, total, want int
+	}{
+		{1, 20, 45, 0},
+		{3, 20, 45, 40},
+		{4, 20, 45, 45},
+		{0, 20, 45, 0},
+		{-5, 20, 45, 0},
+		{math.MaxInt, 20, 45, 45},
+		{math.MaxInt, 10, 0, 0},
+		{math.MaxInt/20 + 2, 20, 45, 45},
+	}
+	for _, tt := range tests {
+		if got := pageOffset(tt.page, tt.pageSize, tt.total); got != tt.want {
+			t.Errorf("pageOffset(%d, %d, %d) = %d, want %d", tt.page, tt.pageSize, tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestPageHits(t *testing.T) {
+	hits := testHits(45)
+	tests := []struct {
+		offset, limit, want int
+	}{
+		{0, 20, 20},
+		{40, 20, 5},
+		{45, 20, 0},
+		{math.MaxInt, 20, 0},
+		{-20, 20, 0},
+		{math.MinInt, 20, 0},
+		{40, math.MaxInt, 5},
+	}
+	for _, tt := range tests {
+		if got := pageHits(hits, tt.offset, tt.limit); len(got) != tt.want {
+			t.Errorf("pageHits(%d, %d) returned %d hits, want %d", tt.offset, tt.limit, len(got), tt.want)
+		}
+	}
+}
+
+// 検索APIのテスト用に記事を入れ替える（post-00 が最も古い。偶数番の本文だけ golang を含む）
+func seedSearchPosts(t *testing.T, n int) {
+	t.Helper()
+	posts := make([]models.BlogPost, n)
+	for i := range posts {
+		body := "日記です。"
+		if i%2 == 0 {
+			body = "golang の記事です。"
+		}
+		posts[i] = models.BlogPost{
+			Slug:        fmt.Sprintf("post-%02d", i),
+			Title:       fmt.Sprintf("記事 %02d", i),
+			Content:     body,
+			Published:   true,
+			CreatedDate: time.Date(2024, 1, 1+i, 0, 0, 0, 0, time.UTC),
+		}
+	}
+	store.ReplacePosts(posts)
+	t.Cleanup(func() { store.ReplacePosts(nil) })
+}
+
+type searchResponse struct {
+	Posts      []searchResult `json:"posts"`
+	Total      int            `json:"total"`
+	Offset     int            `json:"offset"`
+	NextOffset *int           `json:"next_offset"`
+}
+
+func getSearch(t *testing.T, query string) (int, searchResponse) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/search?"+query, nil)
+	searchBlogPosts(c)
+
+	var body searchResponse
+	if w.Code == http.StatusOK {
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: %v", query, err)
+		}
+	}
+	return w.Code, body
+}
+
+func slugsOf(results []searchResult) []string {
+	slugs := make([]string, len(results))
+	for i, r := range results {
+		slugs[i] = r.Slug
+	}
+	return slugs
+}
+
+func TestSearchBlogPostsPages(t *testing.T) {
+	seedSearchPosts(t, 25)
+
+	tests := []struct {
+		query      string
+		total      int
+		offset     int
+		first      string // 先頭の記事（空なら0件）
+		count      int
+		nextOffset int // 0 なら次ページなし
+	}{
+		{"limit=10", 25, 0, "post-24", 10, 10},
+		{"limit=10&page=3", 25, 20, "post-04", 5, 0},
+		{"limit=10&page=4", 25, 25, "", 0, 0},
+		{"limit=10&page=" + strconv.Itoa(math.MaxInt), 25, 25, "", 0, 0},
+		{"limit=10&page=99999999999999999999", 25, 0, "post-24", 10, 10},
+		{"limit=10&page=-1", 25, 0, "post-24", 10, 10},
+		{"limit=10&offset=7", 25, 7, "post-17", 10, 17},
+		{"limit=10&offset=100", 25, 100, "", 0, 0},
+		{"limit=10&offset=&page=2", 25, 10, "post-14", 10, 20},
+		{"limit=5&sort=oldest", 25, 0, "post-00", 5, 5},
+		{"q=golang&limit=10", 13, 0, "", 10, 10},
+		{"q=golang&limit=10&page=2", 13, 10, "", 3, 0},
+	}
+	for _, tt := range tests {
+		code, body := getSearch(t, tt.query)
+		if code != http.StatusOK {
+			t.Errorf("%s: status %d, want 200", tt.query, code)
+			continue
+		}
+		if body.Total != tt.total || body.Offset != tt.offset || len(body.Posts) != tt.count {
+			t.Errorf("%s: total %d offset %d posts %d, want %d %d %d", tt.query, body.Total, body.Offset, len(body.Posts), tt.total, tt.offset, tt.count)
+		}
+		if tt.first != "" && (len(body.Posts) == 0 || body.Posts[0].Slug != tt.first) {
+			t.Errorf("%s: posts %v, want %s first", tt.query, slugsOf(body.Posts), tt.first)
+		}
+		switch {
+		case tt.nextOffset == 0 && body.NextOffset != nil:
+			t.Errorf("%s: next_offset %d, want none", tt.query, *body.NextOffset)
+		case tt.nextOffset != 0 && (body.NextOffset == nil || *body.NextOffset != tt.nextOffset):
+			t.Errorf("%s: next_offset %v, want %d", tt.query, body.NextOffset, tt.nextOffset)
+		}
+	}
+}
+
+func TestSearchBlogPostsNewestFirst(t *testing.T) {
+	seedSearchPosts(t, 25)
+
+	_, body := getSearch(t, "limit=25")
+	for i := 1; i < len(body.Posts); i++ {
+		if body.Posts[i-1].CreatedDate.Before(body.Posts[i].CreatedDate) {
+			t.Fatalf("not newest first: %v", slugsOf(body.Posts))
+		}
+	}
+	// 検索語の一致は偶数番の記事だけ
+	_, body = getSearch(t, "q=golang&limit=25")
+	for _, slug := range slugsOf(body.Posts) {
+		if n, _ := strconv.Atoi(slug[len("post-"):]); n%2 != 0 {
+			t.Errorf("q=golang returned %s", slug)
+		}
+	}
+}
+
+func TestSearchBlogPostsInvalidOffset(t *testing.T) {
+	seedSearchPosts(t, 3)
+	for _, offset := range []string{"-5", "abc", "1.5", "99999999999999999999"} {
+		if code, _ := getSearch(t, "offset="+offset); code != http.StatusBadRequest {
+			t.Errorf("offset=%s: status %d, want 400", offset, code)
+		}
+	}
+}
+
+func TestSortHitsSeriesUnnumberedLast(t *testing.T) {
+	hits := []content.Hit{
+		{Post: models.BlogPost{Slug: "extra", SeriesPart: 0}},
+		{Post: models.BlogPost{Slug: "part-2", SeriesPart: 2}},
+		{Post: models.BlogPost{Slug: "part-1", SeriesPart: 1}},
+	}
+	sortHits(hits, sortSeries)
+
+	want := []string{"part-1", "part-2", "extra"}
+	for i, hit := range hits {
+		if hit.Post.Slug != want[i] {
+			t.Fatalf("order[%d] = %s, want %s", i, hit.Post.Slug, want[i])
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"infohiroki-go/src/models"
+)
+
+// プレビューURLの有効期間
+const previewTokenTTL = 7 * 24 * time.Hour
+
+// 下書き・予約投稿のプレビュー用署名キー（未設定ならプレビュー無効）
+func previewSecret() string {
+	return siteConfig.Secrets.PreviewSecret
+}
+
+// スラッグと有効期限に対するプレビュートークンを生成（<期限のUNIX秒>.<署名>）
+func previewToken(slug string, expires time.Time) string {
+	expiry := strconv.FormatInt(expires.Unix(), 10)
+	return expiry + "." + previewSignature(slug, expiry)
+}
+
+func previewSignature(slug string, expiry string) string {
+	mac := hmac.New(sha256.New, []byte(previewSecret()))
+	mac.Write([]byte(slug + "|" + expiry))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// プレビュートークンを検証（署名が正しく、期限内であること）
+func verifyPreviewToken(slug string, token string, now time.Time) bool {
+	if previewSecret() == "" || token == "" {
+		return false
+	}
+	expiry, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+	expires, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil || now.Unix() >= expires {
+		return false
+	}
+	return hmac.Equal([]byte(previewSignature(slug, expiry)), []byte(signature))
+}
+
+// プレビューURL（有効期限つき）
+func previewURL(slug string, expires time.Time) string {
+	return "/blog/" + slug + "?preview=" + previewToken(slug, expires)
+}
+
+// 非公開記事をログに出力（トークンはデバッグモードのときのみ。本番では管理用APIで発行する）
+func logPreviewURLs(posts []models.BlogPost) {
+	if previewSecret() == "" {
+		return
+	}
+
+	now := time.Now()
+	expires := now.Add(previewTokenTTL)
+	for _, post := range posts {
+		link := "/blog/" + post.Slug
+		if gin.IsDebugging() {
+			link = previewURL(post.Slug, expires)
+		}
+		switch {
+		case !post.Published:
+			fmt.Printf("🔒 下書き: %s\n", link)
+		case post.IsScheduled(now):
+			fmt.Printf("⏰ 予約投稿（%s公開）: %s\n", post.CreatedDate.Format("2006-01-02 15:04"), link)
+		}
+	}
+}
+
+// 管理用API: 非公開記事のプレビューURLを発行
+func listPreviews(c *gin.Context) {
+	if previewSecret() == "" {
+		renderError(c, http.StatusNotFound, "プレビューは無効です", "PREVIEW_SECRET を設定してください。", nil)
+		return
+	}
+
+	now := time.Now()
+	expires := now.Add(previewTokenTTL)
+	previews := []gin.H{}
+	for _, post := range store.Snapshot().Posts() {
+		if post.IsVisible(now) {
+			continue
+		}
+		previews = append(previews, gin.H{
+			"slug":         post.Slug,
+			"title":        post.Title,
+			"draft":        !post.Published,
+			"created_date": post.CreatedDate,
+			"url":          siteBaseURL + previewURL(post.Slug, expires),
+			"expires_at":   expires,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"previews": previews, "total": len(previews)})
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPreviewToken(t *testing.T) {
+	saved := siteConfig.Secrets.PreviewSecret
+	siteConfig.Secrets.PreviewSecret = "test-secret"
+	defer func() { siteConfig.Secrets.PreviewSecret = saved }()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	token := previewToken("draft-post", now.Add(time.Hour))
+
+	if !verifyPreviewToken("draft-post", token, now) {
+		t.Fatal("valid token rejected")
+	}
+	if verifyPreviewToken("draft-post", token, now.Add(time.Hour)) {
+		t.Error("expired token accepted")
+	}
+	if verifyPreviewToken("other-post", token, now) {
+		t.Error("token accepted for another slug")
+	}
+
+	// 期限だけを書き換えたトークンは署名が合わない
+	_, signature, _ := strings.Cut(token, ".")
+	extended := previewToken("draft-post", now.Add(365*24*time.Hour))
+	expiry, _, _ := strings.Cut(extended, ".")
+	if verifyPreviewToken("draft-post", expiry+"."+signature, now.Add(2*time.Hour)) {
+		t.Error("token with a forged expiry accepted")
+	}
+
+	for _, bad := range []string{"", "abc", "." + signature, "x." + signature} {
+		if verifyPreviewToken("draft-post", bad, now) {
+			t.Errorf("malformed token %q accepted", bad)
+		}
+	}
+
+	siteConfig.Secrets.PreviewSecret = ""
+	if verifyPreviewToken("draft-post", token, now) {
+		t.Error("token accepted with previews disabled")
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"infohiroki-go/src/models"
+	"infohiroki-go/src/redirects"
+)
+
+// 現在のリダイレクト表（記事・リダイレクト設定の再読み込みで差し替え）
+var redirectTable atomic.Pointer[redirects.Table]
+
+// パラメータを含まないルート（リダイレクト元との重なりの検証用）
+var fixedRoutes = map[string]bool{}
+
+// 静的ファイルの配信元（URLの接頭辞 → ディレクトリ）
+var staticDirs = map[string]string{
+	"/css/":    "static/css",
+	"/js/":     "static/js",
+	"/images/": "static/images",
+}
+
+// 登録済みのルートを記録（記事の読み込み前に呼ぶ）
+func recordRoutes(routes gin.RoutesInfo) {
+	for _, route := range routes {
+		if route.Method == http.MethodGet && !strings.ContainsAny(route.Path, ":*") {
+			fixedRoutes[route.Path] = true
+		}
+	}
+}
+
+// サイトが既に応答するパスか（固定ルート・記事・静的ファイル）
+func servedPath(path string) bool {
+	if fixedRoutes[path] {
+		return true
+	}
+	if slug, ok := strings.CutPrefix(path, "/blog/"); ok {
+		slug = strings.TrimSuffix(strings.TrimSuffix(slug, ".md"), ".json")
+		if _, _, found := store.Snapshot().Lookup(slug); found {
+			return true
+		}
+	}
+	for prefix, dir := range staticDirs {
+		if name, ok := strings.CutPrefix(path, prefix); ok {
+			if info, err := os.Stat(filepath.Join(dir, filepath.FromSlash(name))); err == nil && !info.IsDir() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// サイトが応答するパスの一覧（固定ルートと記事。正規表現のリダイレクト元との重なりの検証用）
+func knownPaths(posts []models.BlogPost) []string {
+	paths := make([]string, 0, len(fixedRoutes)+len(posts)*3)
+	for path := range fixedRoutes {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, post := range posts {
+		paths = append(paths, "/blog/"+post.Slug, "/blog/"+post.Slug+".md", "/blog/"+post.Slug+".json")
+	}
+	return paths
+}
+
+// 記事の aliases（旧スラッグ）を記事への301リダイレクトに変換。/ で始まる場合はパスとして扱う
+func aliasRules(posts []models.BlogPost) []redirects.Rule {
+	var rules []redirects.Rule
+	for _, post := range posts {
+		for _, alias := range post.Aliases {
+			from := strings.TrimSpace(alias)
+			if from == "" {
+				continue
+			}
+			if !strings.HasPrefix(from, "/") {
+				from = "/blog/" + from
+			}
+			rules = append(rules, redirects.Rule{
+				From:   from,
+				To:     "/blog/" + post.Slug,
+				Source: post.MarkdownPath,
+			})
+		}
+	}
+	return rules
+}
+
+// 設定ファイルのルールと記事の aliases からリダイレクト表を作り直す
+func rebuildRedirects(fileRules []redirects.Rule, posts []models.BlogPost) {
+	table := redirects.New(append(append([]redirects.Rule(nil), fileRules...), aliasRules(posts)...), servedPath, knownPaths(posts))
+	for _, err := range table.Errors() {
+		fmt.Printf("⚠️ リダイレクト設定を無視: %v\n", err)
+	}
+	redirectTable.Store(table)
+	fmt.Printf("↪️ リダイレクト: %d件\n", len(table.Rules()))
+}
+
+// 転送先（.md / .json 付きの旧URLは拡張子なしのルールでも探し、転送先に拡張子を引き継ぐ）
+func matchRedirect(path string) (target string, status int, ok bool) {
+	table := redirectTable.Load()
+	if table == nil {
+		return "", 0, false
+	}
+	if target, status, ok = table.Match(path); ok {
+		return target, status, true
+	}
+	for _, ext := range []string{".md", ".json"} {
+		if base, found := strings.CutSuffix(path, ext); found && strings.HasPrefix(path, "/blog/") {
+			if target, status, ok = table.Match(base); ok && strings.HasPrefix(target, "/blog/") && !strings.Contains(target, "?") {
+				return target + ext, status, true
+			}
+		}
+	}
+	return "", 0, false
+}
+
+// 旧URLなら転送（410は削除済みページとして応答）。応答した場合は true
+func serveRedirect(c *gin.Context) bool {
+	target, status, ok := matchRedirect(c.Request.URL.Path)
+	if !ok {
+		return false
+	}
+	if status == http.StatusGone {
+		renderError(c, http.StatusGone, "ページは削除されました", "お探しのページは削除されました。", nil)
+		return true
+	}
+	// クエリ（?page= など）は転送先に引き継ぐ
+	if query := c.Request.URL.RawQuery; query != "" && !strings.Contains(target, "?") {
+		target += "?" + query
+	}
+	c.Redirect(status, target)
+	c.Abort()
+	return true
+}
+
+// 管理用API: 有効なリダイレクトと無視した設定の一覧
+func listRedirects(c *gin.Context) {
+	table := redirectTable.Load()
+	if table == nil {
+		table = redirects.New(nil, nil, nil)
+	}
+	errors := []string{}
+	for _, err := range table.Errors() {
+		errors = append(errors, err.Error())
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"redirects": table.Rules(),
+		"total":     len(table.Rules()),
+		"errors":    errors,
+	})
+}
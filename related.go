@@ -0,0 +1,76 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"infohiroki-go/src/content"
+	"infohiroki-go/src/models"
+	"infohiroki-go/src/search"
+)
+
+// 記事ページに表示する関連記事の件数
+const relatedPostsLimit = 3
+
+// コンテンツストアの設定（content.related_min_score で関連記事の最低スコアを調整）
+func storeOptions() content.Options {
+	options := content.DefaultOptions
+	options.RelatedMinScore = siteConfig.Content.RelatedMinScore
+	return options
+}
+
+// 関連記事を取得（一覧表示用に要約のみ）
+func findRelatedPosts(snap *content.Snapshot, slug string, limit int, now time.Time) []models.BlogPost {
+	var result []models.BlogPost
+	for _, hit := range snap.Related(slug, limit, now) {
+		result = append(result, models.BlogPost{
+			Slug:        hit.Post.Slug,
+			Title:       hit.Post.Title,
+			Description: hit.Post.Description,
+			Icon:        hit.Post.Icon,
+			CreatedDate: hit.Post.CreatedDate,
+		})
+	}
+	return result
+}
+
+// 関連記事のデバッグ用API（スコアと寄与した語を返す）
+func relatedPostsAPI(c *gin.Context) {
+	snap := store.Snapshot()
+	now := time.Now()
+	slug := c.Param("slug")
+
+	post, _, ok := snap.Lookup(slug)
+	if !ok || !post.IsVisible(now) {
+		renderNotFound(c)
+		return
+	}
+
+	limit := positiveIntParam(c.Query("limit"), store.Options().RelatedLimit)
+
+	type relatedItem struct {
+		Slug        string              `json:"slug"`
+		Title       string              `json:"title"`
+		Score       float64             `json:"score"`
+		SharedTerms []search.TermWeight `json:"shared_terms"`
+	}
+
+	items := []relatedItem{}
+	for _, hit := range snap.Related(slug, limit, now) {
+		items = append(items, relatedItem{
+			Slug:        hit.Post.Slug,
+			Title:       hit.Post.Title,
+			Score:       hit.Score,
+			SharedTerms: snap.RelatedTerms(slug, hit.Post.Slug, 8),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"slug":      slug,
+		"title":     post.Title,
+		"min_score": store.Options().RelatedMinScore,
+		"version":   snap.Version,
+		"related":   items,
+	})
+}
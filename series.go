@@ -0,0 +1,68 @@
+package main
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"infohiroki-go/src/models"
+)
+
+// シリーズ目次を作成（seriesPostsは番号順）
+func buildSeriesNav(seriesPosts []models.BlogPost, current *models.BlogPost) *models.SeriesNav {
+	nav := &models.SeriesNav{
+		Name:  current.Series,
+		Title: current.SeriesDisplayTitle(),
+		Part:  current.SeriesPart,
+	}
+
+	for i, post := range seriesPosts {
+		summary := models.BlogPost{
+			Slug:        post.Slug,
+			Title:       post.Title,
+			Description: post.Description,
+			Icon:        post.Icon,
+			CreatedDate: post.CreatedDate,
+			SeriesPart:  post.SeriesPart,
+		}
+		nav.Posts = append(nav.Posts, summary)
+
+		if post.Slug != current.Slug {
+			continue
+		}
+		if i > 0 {
+			prev := seriesPosts[i-1]
+			nav.Prev = &models.BlogPost{Slug: prev.Slug, Title: prev.Title, Icon: prev.Icon, SeriesPart: prev.SeriesPart}
+		}
+		if i < len(seriesPosts)-1 {
+			next := seriesPosts[i+1]
+			nav.Next = &models.BlogPost{Slug: next.Slug, Title: next.Title, Icon: next.Icon, SeriesPart: next.SeriesPart}
+		}
+	}
+
+	// シリーズが1記事だけなら目次は不要
+	if len(nav.Posts) < 2 {
+		return nil
+	}
+	return nav
+}
+
+// シリーズ別記事一覧（番号順）
+func seriesPage(c *gin.Context) {
+	name := models.NormalizeTag(c.Param("name"))
+	posts := store.Snapshot().InSeries(name, time.Now())
+	if len(posts) == 0 {
+		renderNotFound(c)
+		return
+	}
+
+	title := posts[0].SeriesDisplayTitle()
+	renderBlogList(c, blogListing{
+		path:        "/blog/series/" + url.PathEscape(name),
+		title:       title + " | infoHiroki",
+		heading:     "📚 " + title,
+		description: "infoHirokiブログの連載「" + title + "」の記事一覧",
+		filter:      postFilter{Series: name},
+		defaultSort: sortSeries,
+	})
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"infohiroki-go/src/models"
+)
+
+func seriesPost(slug string, part int, day int) models.BlogPost {
+	return models.BlogPost{
+		Slug:        slug,
+		Title:       "回 " + slug,
+		Content:     "# 本文\n",
+		Series:      "intro",
+		SeriesTitle: "Go入門",
+		SeriesPart:  part,
+		Published:   true,
+		CreatedDate: time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestBuildSeriesNav(t *testing.T) {
+	posts := []models.BlogPost{seriesPost("s1", 1, 1), seriesPost("s2", 2, 2), seriesPost("s3", 3, 3)}
+
+	nav := buildSeriesNav(posts, &posts[1])
+	if nav == nil || nav.Title != "Go入門" || len(nav.Posts) != 3 {
+		t.Fatalf("nav = %+v", nav)
+	}
+	if nav.Prev == nil || nav.Prev.Slug != "s1" || nav.Next == nil || nav.Next.Slug != "s3" {
+		t.Errorf("prev %+v, next %+v", nav.Prev, nav.Next)
+	}
+	if first := buildSeriesNav(posts, &posts[0]); first.Prev != nil || first.Next.Slug != "s2" {
+		t.Errorf("first: prev %+v, next %+v", first.Prev, first.Next)
+	}
+	if last := buildSeriesNav(posts, &posts[2]); last.Next != nil || last.Prev.Slug != "s2" {
+		t.Errorf("last: prev %+v, next %+v", last.Prev, last.Next)
+	}
+	// 1記事だけのシリーズは目次なし
+	if nav := buildSeriesNav(posts[:1], &posts[0]); nav != nil {
+		t.Errorf("single post nav = %+v", nav)
+	}
+}
+
+func TestSeriesRoutes(t *testing.T) {
+	// 作成日の順ではなく番号順に並ぶ（番号なしは末尾）
+	draft := seriesPost("s-draft", 4, 5)
+	draft.Published = false
+	seedPosts(t, seriesPost("s2", 2, 1), seriesPost("s1", 1, 3), seriesPost("s3", 0, 2), draft)
+
+	w := get(t, "/blog/series/intro")
+	if w.Code != http.StatusOK {
+		t.Fatalf("series page: status %d", w.Code)
+	}
+	body := w.Body.String()
+	i1, i2, i3 := strings.Index(body, "/blog/s1"), strings.Index(body, "/blog/s2"), strings.Index(body, "/blog/s3")
+	if i1 < 0 || i2 < 0 || i3 < 0 || !(i1 < i2 && i2 < i3) {
+		t.Errorf("series order: s1 at %d, s2 at %d, s3 at %d", i1, i2, i3)
+	}
+	if strings.Contains(body, "/blog/s-draft") {
+		t.Error("draft listed in series")
+	}
+
+	if w := get(t, "/blog/series/none"); w.Code != http.StatusNotFound {
+		t.Errorf("unknown series: status %d", w.Code)
+	}
+
+	// 記事ページのシリーズ目次
+	body = get(t, "/blog/s2").Body.String()
+	for _, want := range []string{`href="/blog/series/intro"`, "全3回", `href="/blog/s1" class="series-nav-link"`, `href="/blog/s3" class="series-nav-link"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("post page: missing %s", want)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"infohiroki-go/src/models"
+)
+
+type sitemapURLSet struct {
+	URLs []struct {
+		Loc     string `xml:"loc"`
+		LastMod string `xml:"lastmod"`
+	} `xml:"url"`
+}
+
+func getSitemap(t *testing.T) sitemapURLSet {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil)
+	sitemapXML(c)
+
+	var set sitemapURLSet
+	if err := xml.Unmarshal(w.Body.Bytes(), &set); err != nil {
+		t.Fatalf("sitemap is not valid XML: %v\n%s", err, w.Body.String())
+	}
+	return set
+}
+
+func TestSitemapPostEntries(t *testing.T) {
+	store.ReplacePosts([]models.BlogPost{
+		{
+			Slug:        "updated",
+			Published:   true,
+			CreatedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			UpdatedAt:   time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			Slug:        "a&b <c>",
+			Published:   true,
+			CreatedDate: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+	})
+	t.Cleanup(func() { store.ReplacePosts(nil) })
+
+	lastmod := map[string]string{}
+	for _, u := range getSitemap(t).URLs {
+		lastmod[u.Loc] = u.LastMod
+	}
+
+	// 更新日があれば更新日
+	if got := lastmod[siteBaseURL+"/blog/updated"]; got != "2024-03-01" {
+		t.Errorf("lastmod of updated post = %q, want 2024-03-01", got)
+	}
+	// スラッグはURLとしてもXMLとしてもエスケープする
+	if got, ok := lastmod[siteBaseURL+"/blog/a&b%20%3Cc%3E"]; !ok || got != "2024-02-01" {
+		t.Errorf("escaped post entry missing or wrong: %v", lastmod)
+	}
+}
@@ -0,0 +1,261 @@
+// Package config holds the site configuration.
+//
+// Values come from Default, then optional YAML files (each overriding the
+// keys it sets), then environment variables (an empty variable also
+// overrides, e.g. GA_MEASUREMENT_ID= turns analytics off). Load validates
+// the result.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the whole configuration
+type Config struct {
+	Site      Site      `yaml:"site"`
+	Content   Content   `yaml:"content"`
+	Analytics Analytics `yaml:"analytics"`
+	Cache     Cache     `yaml:"cache"`
+	Features  Features  `yaml:"features"`
+	Secrets   Secrets   `yaml:"secrets"`
+}
+
+// Site is where the site is served
+type Site struct {
+	BaseURL     string   `yaml:"base_url"`     // 絶対URLの生成に使う公開URL（空なら http://localhost:<port>）
+	Port        string   `yaml:"port"`         // 待ち受けポート
+	ContentURLs []string `yaml:"content_urls"` // 記事本文でサイト内リンクとして扱うURL（BaseURL は常に含む）
+}
+
+// Content is where articles and their files live
+type Content struct {
+	ArticlesDir     string  `yaml:"articles_dir"`
+	TaxonomyFile    string  `yaml:"taxonomy_file"`  // 空なら articles_dir/taxonomy.yml
+	RedirectsFile   string  `yaml:"redirects_file"` // 空なら articles_dir/redirects.yml
+	RelatedMinScore float64 `yaml:"related_min_score"`
+}
+
+// Analytics holds tracking IDs; empty IDs disable the tag
+type Analytics struct {
+	GoogleAnalyticsID string `yaml:"google_analytics_id"`
+}
+
+// Cache is where generated files are kept and how long clients may cache them
+type Cache struct {
+	ImageDir   string `yaml:"image_dir"`   // 記事画像の縮小版・WebP
+	OGDir      string `yaml:"og_dir"`      // OGP画像
+	WarmImages bool   `yaml:"warm_images"` // 起動時に縮小版を生成
+	MaxAge     int    `yaml:"max_age"`     // 生成画像の Cache-Control max-age（秒）
+}
+
+// Features switches optional behaviour on and off
+type Features struct {
+	WatchArticles bool `yaml:"watch_articles"` // 記事ディレクトリの変更を自動で反映
+	OGImages      bool `yaml:"og_images"`      // 記事のOGP画像を生成
+	LLMSTxt       bool `yaml:"llms_txt"`       // /llms.txt・/llms-full.txt を配信
+	Indexing      bool `yaml:"indexing"`       // 検索エンジンのクロールを許可（false なら robots.txt で拒否）
+}
+
+// Secrets enable signed previews and the admin API; prefer setting them by environment
+type Secrets struct {
+	PreviewSecret string `yaml:"preview_secret"`
+	AdminToken    string `yaml:"admin_token"`
+}
+
+// Default returns the configuration for local development
+func Default() Config {
+	return Config{
+		Site: Site{
+			Port: "8080",
+		},
+		Content: Content{
+			ArticlesDir:     "articles",
+			RelatedMinScore: 0.05,
+		},
+		Cache: Cache{
+			ImageDir:   "cache/images",
+			OGDir:      "cache/og",
+			WarmImages: true,
+			MaxAge:     86400,
+		},
+		Features: Features{
+			WatchArticles: true,
+			OGImages:      true,
+			LLMSTxt:       true,
+			Indexing:      true,
+		},
+	}
+}
+
+// Load reads the YAML files in order (empty paths and missing files are skipped),
+// applies environment overrides and validates the result.
+// Unknown keys in a file are an error.
+func Load(paths ...string) (Config, error) {
+	cfg := Default()
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		switch {
+		case os.IsNotExist(err):
+		case err != nil:
+			return cfg, err
+		default:
+			if err := decode(data, &cfg); err != nil {
+				return cfg, fmt.Errorf("%s: %w", path, err)
+			}
+		}
+	}
+
+	envErr := cfg.applyEnv(os.LookupEnv)
+	cfg.FillDerived()
+	return cfg, errors.Join(envErr, cfg.Validate())
+}
+
+// 書かれた項目だけを上書き（綴り違いなど未知の項目はエラー）
+func decode(data []byte, cfg *Config) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+// 環境変数による上書き（変数名 → 設定項目）
+var envOverrides = []struct {
+	name  string
+	apply func(cfg *Config, value string) error
+}{
+	{"SITE_BASE_URL", func(cfg *Config, v string) error { cfg.Site.BaseURL = v; return nil }},
+	{"PORT", func(cfg *Config, v string) error { cfg.Site.Port = v; return nil }},
+	{"ARTICLES_DIR", func(cfg *Config, v string) error { cfg.Content.ArticlesDir = v; return nil }},
+	{"RELATED_MIN_SCORE", func(cfg *Config, v string) error { return parseFloat(v, &cfg.Content.RelatedMinScore) }},
+	{"GA_MEASUREMENT_ID", func(cfg *Config, v string) error { cfg.Analytics.GoogleAnalyticsID = v; return nil }},
+	{"IMAGE_CACHE_DIR", func(cfg *Config, v string) error { cfg.Cache.ImageDir = v; return nil }},
+	{"OG_CACHE_DIR", func(cfg *Config, v string) error { cfg.Cache.OGDir = v; return nil }},
+	{"IMAGE_WARM", func(cfg *Config, v string) error { return parseBool(v, &cfg.Cache.WarmImages) }},
+	{"CACHE_MAX_AGE", func(cfg *Config, v string) error { return parseInt(v, &cfg.Cache.MaxAge) }},
+	{"WATCH_ARTICLES", func(cfg *Config, v string) error { return parseBool(v, &cfg.Features.WatchArticles) }},
+	{"OG_IMAGES", func(cfg *Config, v string) error { return parseBool(v, &cfg.Features.OGImages) }},
+	{"LLMS_TXT", func(cfg *Config, v string) error { return parseBool(v, &cfg.Features.LLMSTxt) }},
+	{"INDEXING", func(cfg *Config, v string) error { return parseBool(v, &cfg.Features.Indexing) }},
+	{"PREVIEW_SECRET", func(cfg *Config, v string) error { cfg.Secrets.PreviewSecret = v; return nil }},
+	{"ADMIN_TOKEN", func(cfg *Config, v string) error { cfg.Secrets.AdminToken = v; return nil }},
+}
+
+func (cfg *Config) applyEnv(lookup func(string) (string, bool)) error {
+	var errs []error
+	for _, override := range envOverrides {
+		if value, ok := lookup(override.name); ok {
+			if err := override.apply(cfg, strings.TrimSpace(value)); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", override.name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func parseBool(value string, dst *bool) error {
+	switch strings.ToLower(value) {
+	case "on", "yes":
+		*dst = true
+		return nil
+	case "off", "no":
+		*dst = false
+		return nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("true / false（on / off）で指定してください: %q", value)
+	}
+	*dst = b
+	return nil
+}
+
+func parseInt(value string, dst *int) error {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("整数で指定してください: %q", value)
+	}
+	*dst = n
+	return nil
+}
+
+func parseFloat(value string, dst *float64) error {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("数値で指定してください: %q", value)
+	}
+	*dst = f
+	return nil
+}
+
+// FillDerived fills in the optional fields from the others (Load does this after reading the files)
+func (cfg *Config) FillDerived() {
+	cfg.Site.BaseURL = strings.TrimRight(cfg.Site.BaseURL, "/")
+	if cfg.Site.BaseURL == "" {
+		cfg.Site.BaseURL = "http://localhost:" + cfg.Site.Port
+	}
+	if cfg.Content.TaxonomyFile == "" {
+		cfg.Content.TaxonomyFile = filepath.Join(cfg.Content.ArticlesDir, "taxonomy.yml")
+	}
+	if cfg.Content.RedirectsFile == "" {
+		cfg.Content.RedirectsFile = filepath.Join(cfg.Content.ArticlesDir, "redirects.yml")
+	}
+	for i, u := range cfg.Site.ContentURLs {
+		cfg.Site.ContentURLs[i] = strings.TrimRight(u, "/")
+	}
+}
+
+// GA4の測定ID
+var gaIDPattern = regexp.MustCompile(`^G-[A-Z0-9]+$`)
+
+// Validate reports every invalid value at once
+func (cfg Config) Validate() error {
+	var errs []error
+	invalid := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	for _, u := range append([]string{cfg.Site.BaseURL}, cfg.Site.ContentURLs...) {
+		parsed, err := url.Parse(u)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" || (parsed.Path != "" && parsed.Path != "/") || parsed.RawQuery != "" {
+			invalid("site: %q は http(s)://ホスト 形式のURLで指定してください", u)
+		}
+	}
+	if port, err := strconv.Atoi(cfg.Site.Port); err != nil || port < 1 || port > 65535 {
+		invalid("site.port: %q は 1〜65535 で指定してください", cfg.Site.Port)
+	}
+
+	if cfg.Content.ArticlesDir == "" {
+		invalid("content.articles_dir を指定してください")
+	}
+	if cfg.Content.RelatedMinScore < 0 || cfg.Content.RelatedMinScore > 1 {
+		invalid("content.related_min_score: %v は 0〜1 で指定してください", cfg.Content.RelatedMinScore)
+	}
+
+	if id := cfg.Analytics.GoogleAnalyticsID; id != "" && !gaIDPattern.MatchString(id) {
+		invalid("analytics.google_analytics_id: %q は G-XXXXXXXXXX 形式で指定してください", id)
+	}
+
+	if cfg.Cache.ImageDir == "" || cfg.Cache.OGDir == "" {
+		invalid("cache.image_dir・cache.og_dir を指定してください")
+	}
+	if cfg.Cache.MaxAge < 0 {
+		invalid("cache.max_age: %d は 0 以上で指定してください", cfg.Cache.MaxAge)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, dir, name, body string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadRejectsUnknownKeys(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "config.yml", "site:\n  base_ulr: https://example.com\n")
+	_, err := Load(path)
+	if err == nil || !strings.Contains(err.Error(), "base_ulr") {
+		t.Fatalf("Load() error = %v, want unknown field base_ulr", err)
+	}
+}
+
+func TestLoadOverlaysFiles(t *testing.T) {
+	dir := t.TempDir()
+	base := writeFile(t, dir, "config.yml", "site:\n  port: \"9000\"\ncontent:\n  related_min_score: 0.2\n")
+	prod := writeFile(t, dir, "config.production.yml", "site:\n  base_url: https://example.com/\nanalytics:\n  google_analytics_id: G-TEST123\n")
+
+	cfg, err := Load(base, prod, filepath.Join(dir, "missing.yml"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Site.Port != "9000" || cfg.Content.RelatedMinScore != 0.2 {
+		t.Errorf("base values lost: port %q, related_min_score %v", cfg.Site.Port, cfg.Content.RelatedMinScore)
+	}
+	if cfg.Site.BaseURL != "https://example.com" || cfg.Analytics.GoogleAnalyticsID != "G-TEST123" {
+		t.Errorf("overlay not applied: base_url %q, analytics %q", cfg.Site.BaseURL, cfg.Analytics.GoogleAnalyticsID)
+	}
+	if cfg.Cache.MaxAge != Default().Cache.MaxAge {
+		t.Errorf("default cache.max_age lost: %d", cfg.Cache.MaxAge)
+	}
+}
+
+// 共通の設定ファイルだけではローカル向け（本番のURL・アナリティクスを出さない）
+func TestRepositoryConfigIsLocal(t *testing.T) {
+	cfg, err := Load("../../config.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Site.BaseURL != "http://localhost:"+cfg.Site.Port {
+		t.Errorf("base_url = %q, want localhost", cfg.Site.BaseURL)
+	}
+	if cfg.Analytics.GoogleAnalyticsID != "" {
+		t.Errorf("google_analytics_id = %q, want empty", cfg.Analytics.GoogleAnalyticsID)
+	}
+
+	prod, err := Load("../../config.yml", "../../config.production.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(prod.Site.BaseURL, "https://") || prod.Analytics.GoogleAnalyticsID == "" {
+		t.Errorf("production config: base_url %q, analytics %q", prod.Site.BaseURL, prod.Analytics.GoogleAnalyticsID)
+	}
+}
@@ -0,0 +1,405 @@
+package content
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"infohiroki-go/src/models"
+	"infohiroki-go/src/search"
+)
+
+// Snapshot is an immutable view of all posts and pages.
+// Callers must treat the returned slices as read-only.
+type Snapshot struct {
+	Version  uint64
+	LoadedAt time.Time
+
+	posts      []models.BlogPost // 作成日の降順
+	pages      []models.Page
+	bySlug     map[string]int
+	byMonth    map[string][]int // "2006-01" → postsのインデックス
+	pageMap    map[string]int
+	index      *search.Index    // 全文検索インデックス（非公開記事も含む）
+	related    *search.Related  // 関連記事（TF-IDFのコサイン類似度）
+	byTag      map[string][]int // タグ → postsのインデックス
+	byCategory map[string][]int // カテゴリ → postsのインデックス
+	bySeries   map[string][]int // シリーズ → postsのインデックス（番号順）
+}
+
+// TermCount is a tag or category with the number of visible posts carrying it
+type TermCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// Store holds the current snapshot behind an atomic pointer so readers never block
+type Store struct {
+	mu      sync.Mutex // 書き込み（差し替え）の直列化
+	current atomic.Pointer[Snapshot]
+	options Options
+}
+
+// Options tunes how snapshots are built
+type Options struct {
+	RelatedLimit    int     // 記事ごとに保持する関連記事の候補数
+	RelatedMinScore float64 // 関連記事とみなすコサイン類似度の下限
+}
+
+// DefaultOptions are used by NewStore
+var DefaultOptions = Options{
+	RelatedLimit:    10,
+	RelatedMinScore: 0.05,
+}
+
+// NewStore returns a store with an empty snapshot and DefaultOptions
+func NewStore() *Store {
+	return NewStoreWithOptions(DefaultOptions)
+}
+
+// NewStoreWithOptions returns a store with an empty snapshot
+func NewStoreWithOptions(options Options) *Store {
+	s := &Store{options: options}
+	s.current.Store(newSnapshot(0, nil, nil, options))
+	return s
+}
+
+// Options returns the options snapshots are built with
+func (s *Store) Options() Options {
+	return s.options
+}
+
+// Snapshot returns the current snapshot; hold on to it for the whole request
+func (s *Store) Snapshot() *Snapshot {
+	return s.current.Load()
+}
+
+// ReplacePosts swaps in a new snapshot built from posts, keeping the current pages
+func (s *Store) ReplacePosts(posts []models.BlogPost) *Snapshot {
+	return s.Update(func(_ []models.BlogPost, pages []models.Page) ([]models.BlogPost, []models.Page) {
+		return posts, pages
+	})
+}
+
+// ReplacePages swaps in a new snapshot built from pages, keeping the current posts
+func (s *Store) ReplacePages(pages []models.Page) *Snapshot {
+	return s.Update(func(posts []models.BlogPost, _ []models.Page) ([]models.BlogPost, []models.Page) {
+		return posts, pages
+	})
+}
+
+// Update builds a new snapshot from the current one. fn receives copies and may modify them freely.
+func (s *Store) Update(fn func(posts []models.BlogPost, pages []models.Page) ([]models.BlogPost, []models.Page)) *Snapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old := s.current.Load()
+	posts, pages := fn(append([]models.BlogPost(nil), old.posts...), append([]models.Page(nil), old.pages...))
+
+	snap := newSnapshot(old.Version+1, posts, pages, s.options)
+	s.current.Store(snap)
+	return snap
+}
+
+func newSnapshot(version uint64, posts []models.BlogPost, pages []models.Page, options Options) *Snapshot {
+	snap := &Snapshot{
+		Version:  version,
+		LoadedAt: time.Now(),
+		posts:    append([]models.BlogPost(nil), posts...),
+		pages:    append([]models.Page(nil), pages...),
+		bySlug:   make(map[string]int, len(posts)),
+		byMonth:  map[string][]int{},
+		pageMap:  make(map[string]int, len(pages)),
+
+		byTag:      map[string][]int{},
+		byCategory: map[string][]int{},
+		bySeries:   map[string][]int{},
+	}
+
+	// 新しい記事が先頭に来るように作成日の降順でソート（同日はスラッグ順）
+	sort.SliceStable(snap.posts, func(i, j int) bool {
+		a, b := snap.posts[i], snap.posts[j]
+		if !a.CreatedDate.Equal(b.CreatedDate) {
+			return a.CreatedDate.After(b.CreatedDate)
+		}
+		return a.Slug < b.Slug
+	})
+
+	for i, post := range snap.posts {
+		if _, ok := snap.bySlug[post.Slug]; !ok {
+			snap.bySlug[post.Slug] = i
+		}
+		month := post.CreatedDate.Format("2006-01")
+		snap.byMonth[month] = append(snap.byMonth[month], i)
+
+		for _, tag := range post.Tags {
+			snap.byTag[tag] = append(snap.byTag[tag], i)
+		}
+		if post.Category != "" {
+			snap.byCategory[post.Category] = append(snap.byCategory[post.Category], i)
+		}
+		if post.Series != "" {
+			snap.bySeries[post.Series] = append(snap.bySeries[post.Series], i)
+		}
+	}
+
+	// シリーズは番号順（番号なしは古い順で末尾）
+	for _, indexes := range snap.bySeries {
+		sort.SliceStable(indexes, func(i, j int) bool {
+			return snap.posts[indexes[i]].SeriesBefore(&snap.posts[indexes[j]])
+		})
+	}
+	for i, page := range snap.pages {
+		snap.pageMap[page.Slug] = i
+	}
+
+	docs := make([]search.Document, len(snap.posts))
+	for i, post := range snap.posts {
+		docs[i] = searchDocument(post)
+	}
+	snap.index = search.NewIndex(docs)
+	snap.related = search.NewRelated(docs, options.RelatedLimit, options.RelatedMinScore)
+
+	return snap
+}
+
+// 記事を検索用の文書に変換（見出しは別フィールドとして重み付け）
+func searchDocument(post models.BlogPost) search.Document {
+	var headings []string
+	inFence := false
+	for _, line := range strings.Split(post.Content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+			continue
+		}
+		if !inFence && strings.HasPrefix(trimmed, "#") {
+			headings = append(headings, strings.TrimLeft(trimmed, "# "))
+		}
+	}
+
+	return search.Document{
+		ID:          post.Slug,
+		Title:       post.Title,
+		Description: post.Description,
+		Tags:        strings.Join(append(append([]string{}, post.Tags...), post.Category, post.SeriesTitle), " "),
+		Headings:    strings.Join(headings, "\n"),
+		Body:        post.Content,
+	}
+}
+
+// Posts returns every post, newest first
+func (s *Snapshot) Posts() []models.BlogPost {
+	return s.posts
+}
+
+// Pages returns every static page
+func (s *Snapshot) Pages() []models.Page {
+	return s.pages
+}
+
+// Len returns the number of posts
+func (s *Snapshot) Len() int {
+	return len(s.posts)
+}
+
+// At returns the post at index i of Posts
+func (s *Snapshot) At(i int) models.BlogPost {
+	return s.posts[i]
+}
+
+// Lookup finds a post by slug and returns a copy along with its index in Posts
+func (s *Snapshot) Lookup(slug string) (models.BlogPost, int, bool) {
+	i, ok := s.bySlug[slug]
+	if !ok {
+		return models.BlogPost{}, -1, false
+	}
+	return s.posts[i], i, true
+}
+
+// Page finds a static page by slug
+func (s *Snapshot) Page(slug string) (models.Page, bool) {
+	i, ok := s.pageMap[slug]
+	if !ok {
+		return models.Page{}, false
+	}
+	return s.pages[i], true
+}
+
+// Visible returns a new slice of posts visible at now (published and not scheduled), newest first
+func (s *Snapshot) Visible(now time.Time) []models.BlogPost {
+	result := make([]models.BlogPost, 0, len(s.posts))
+	for _, post := range s.posts {
+		if post.IsVisible(now) {
+			result = append(result, post)
+		}
+	}
+	return result
+}
+
+// Neighbors returns the nearest visible posts around index i: newer (next) and older (prev)
+func (s *Snapshot) Neighbors(i int, now time.Time) (newer, older *models.BlogPost) {
+	for j := i - 1; j >= 0; j-- {
+		if s.posts[j].IsVisible(now) {
+			newer = &s.posts[j]
+			break
+		}
+	}
+	for j := i + 1; j < len(s.posts); j++ {
+		if s.posts[j].IsVisible(now) {
+			older = &s.posts[j]
+			break
+		}
+	}
+	return newer, older
+}
+
+// InMonth returns visible posts created in the given year and month, newest first
+func (s *Snapshot) InMonth(year int, month time.Month, now time.Time) []models.BlogPost {
+	key := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC).Format("2006-01")
+	return s.visibleAt(s.byMonth[key], now)
+}
+
+// ArchiveMonth is the number of visible posts in one month
+type ArchiveMonth struct {
+	Year  int `json:"year"`
+	Month int `json:"month"`
+	Count int `json:"count"`
+}
+
+// ArchiveYear groups the months of one year, newest month first
+type ArchiveYear struct {
+	Year   int            `json:"year"`
+	Count  int            `json:"count"`
+	Months []ArchiveMonth `json:"months"`
+}
+
+// Archive returns per-year and per-month counts of visible posts, newest first
+func (s *Snapshot) Archive(now time.Time) []ArchiveYear {
+	var years []ArchiveYear
+	for _, key := range s.Months() {
+		count := len(s.visibleAt(s.byMonth[key], now))
+		if count == 0 {
+			continue
+		}
+
+		t, err := time.Parse("2006-01", key)
+		if err != nil {
+			continue
+		}
+		if len(years) == 0 || years[len(years)-1].Year != t.Year() {
+			years = append(years, ArchiveYear{Year: t.Year()})
+		}
+		year := &years[len(years)-1]
+		year.Count += count
+		year.Months = append(year.Months, ArchiveMonth{Year: t.Year(), Month: int(t.Month()), Count: count})
+	}
+	return years
+}
+
+// Hit is a search match with its relevance score
+type Hit struct {
+	Post  models.BlogPost
+	Score float64
+}
+
+// Search returns posts visible at now that match query, best match first
+func (s *Snapshot) Search(query string, now time.Time) []Hit {
+	results := s.index.Search(query)
+	hits := make([]Hit, 0, len(results))
+	for _, result := range results {
+		if i, ok := s.bySlug[result.ID]; ok && s.posts[i].IsVisible(now) {
+			hits = append(hits, Hit{Post: s.posts[i], Score: result.Score})
+		}
+	}
+	return hits
+}
+
+// WithTag returns visible posts carrying tag, newest first
+func (s *Snapshot) WithTag(tag string, now time.Time) []models.BlogPost {
+	return s.visibleAt(s.byTag[models.NormalizeTag(tag)], now)
+}
+
+// InCategory returns visible posts in category, newest first
+func (s *Snapshot) InCategory(category string, now time.Time) []models.BlogPost {
+	return s.visibleAt(s.byCategory[models.NormalizeTag(category)], now)
+}
+
+// InSeries returns visible posts of a series in reading order
+func (s *Snapshot) InSeries(name string, now time.Time) []models.BlogPost {
+	return s.visibleAt(s.bySeries[models.NormalizeTag(name)], now)
+}
+
+// Related returns up to limit visible posts similar to slug, most similar first
+func (s *Snapshot) Related(slug string, limit int, now time.Time) []Hit {
+	var hits []Hit
+	for _, result := range s.related.Similar(slug) {
+		if len(hits) >= limit {
+			break
+		}
+		if i, ok := s.bySlug[result.ID]; ok && s.posts[i].IsVisible(now) {
+			hits = append(hits, Hit{Post: s.posts[i], Score: result.Score})
+		}
+	}
+	return hits
+}
+
+// RelatedTerms returns the n terms contributing most to the similarity of two posts
+func (s *Snapshot) RelatedTerms(a, b string, n int) []search.TermWeight {
+	return s.related.SharedTerms(a, b, n)
+}
+
+// Tags returns every tag with its visible post count, most used first
+func (s *Snapshot) Tags(now time.Time) []TermCount {
+	return s.countVisible(s.byTag, now)
+}
+
+// Categories returns every category with its visible post count, most used first
+func (s *Snapshot) Categories(now time.Time) []TermCount {
+	return s.countVisible(s.byCategory, now)
+}
+
+func (s *Snapshot) visibleAt(indexes []int, now time.Time) []models.BlogPost {
+	result := make([]models.BlogPost, 0, len(indexes))
+	for _, i := range indexes {
+		if s.posts[i].IsVisible(now) {
+			result = append(result, s.posts[i])
+		}
+	}
+	return result
+}
+
+func (s *Snapshot) countVisible(terms map[string][]int, now time.Time) []TermCount {
+	counts := make([]TermCount, 0, len(terms))
+	for name, indexes := range terms {
+		count := 0
+		for _, i := range indexes {
+			if s.posts[i].IsVisible(now) {
+				count++
+			}
+		}
+		if count > 0 {
+			counts = append(counts, TermCount{Name: name, Count: count})
+		}
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	return counts
+}
+
+// Months returns the "2006-01" keys that have at least one post, newest first
+func (s *Snapshot) Months() []string {
+	months := make([]string, 0, len(s.byMonth))
+	for month := range s.byMonth {
+		months = append(months, month)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(months)))
+	return months
+}
@@ -0,0 +1,242 @@
+package content
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"infohiroki-go/src/models"
+)
+
+var testNow = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func testPost(slug string, created time.Time, tags []string, series string, part int) models.BlogPost {
+	return models.BlogPost{
+		Slug:        slug,
+		Title:       slug,
+		Content:     "golang " + slug,
+		CreatedDate: created,
+		Published:   true,
+		Tags:        tags,
+		Series:      series,
+		SeriesPart:  part,
+	}
+}
+
+func testPosts(n int) []models.BlogPost {
+	posts := make([]models.BlogPost, n)
+	for i := range posts {
+		created := time.Date(2024, time.Month(i%12+1), 1, 0, 0, 0, 0, time.UTC)
+		posts[i] = testPost(fmt.Sprintf("post-%02d", i), created, []string{"go"}, "intro", i+1)
+	}
+	return posts
+}
+
+// ウォッチャーの差分リロードと同じく、1件だけ差し替えて残りはそのまま
+func replaceOne(post models.BlogPost) func([]models.BlogPost, []models.Page) ([]models.BlogPost, []models.Page) {
+	return func(posts []models.BlogPost, pages []models.Page) ([]models.BlogPost, []models.Page) {
+		for i := range posts {
+			if posts[i].Slug == post.Slug {
+				posts[i] = post
+				return posts, pages
+			}
+		}
+		return append(posts, post), pages
+	}
+}
+
+func removeOne(slug string) func([]models.BlogPost, []models.Page) ([]models.BlogPost, []models.Page) {
+	return func(posts []models.BlogPost, pages []models.Page) ([]models.BlogPost, []models.Page) {
+		result := posts[:0]
+		for _, post := range posts {
+			if post.Slug != slug {
+				result = append(result, post)
+			}
+		}
+		return result, pages
+	}
+}
+
+func slugs(posts []models.BlogPost) []string {
+	result := make([]string, len(posts))
+	for i, post := range posts {
+		result[i] = post.Slug
+	}
+	return result
+}
+
+// 読み込み中に差し替えが走っても、1つのスナップショットの中身は一貫している（-race で確認）
+func TestStoreConcurrentReadersAndUpdates(t *testing.T) {
+	s := NewStore()
+	s.ReplacePosts(testPosts(20))
+
+	stop := make(chan struct{})
+	var readers sync.WaitGroup
+	for r := 0; r < 4; r++ {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				snap := s.Snapshot()
+				visible := snap.Visible(testNow)
+				if len(visible) != snap.Len() {
+					t.Errorf("version %d: %d visible of %d posts", snap.Version, len(visible), snap.Len())
+					return
+				}
+				for i := 0; i < snap.Len(); i++ {
+					newer, older := snap.Neighbors(i, testNow)
+					if i > 0 && (newer == nil || newer.Slug != snap.At(i-1).Slug) {
+						t.Errorf("version %d: wrong newer neighbor at %d", snap.Version, i)
+						return
+					}
+					if i < snap.Len()-1 && (older == nil || older.Slug != snap.At(i+1).Slug) {
+						t.Errorf("version %d: wrong older neighbor at %d", snap.Version, i)
+						return
+					}
+				}
+				for _, hit := range snap.Search("golang", testNow) {
+					if _, _, ok := snap.Lookup(hit.Post.Slug); !ok {
+						t.Errorf("version %d: search hit %q not in snapshot", snap.Version, hit.Post.Slug)
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 50; i++ {
+		switch i % 3 {
+		case 0:
+			s.ReplacePosts(testPosts(10 + i%7))
+		case 1:
+			created := time.Date(2024, time.Month(i%12+1), 15, 0, 0, 0, 0, time.UTC)
+			s.Update(replaceOne(testPost(fmt.Sprintf("post-%02d", i%10), created, []string{"go", "web"}, "intro", i)))
+		case 2:
+			s.Update(removeOne(fmt.Sprintf("post-%02d", i%10)))
+		}
+	}
+	close(stop)
+	readers.Wait()
+}
+
+// 1件の再読み込み・削除のあともスラッグ・タグ・月・シリーズの索引が記事一覧と一致する
+func TestStoreIndexesAfterSingleFileUpdate(t *testing.T) {
+	s := NewStore()
+	s.ReplacePosts([]models.BlogPost{
+		testPost("a", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), []string{"go"}, "intro", 1),
+		testPost("b", time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC), []string{"go", "web"}, "intro", 2),
+		testPost("c", time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC), []string{"web"}, "", 0),
+	})
+
+	// b を別の月・タグ・シリーズ番号で再読み込み
+	snap := s.Update(replaceOne(testPost("b", time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC), []string{"rust"}, "intro", 0)))
+	assertIndexes(t, snap)
+
+	if got := slugs(snap.WithTag("go", testNow)); fmt.Sprint(got) != "[a]" {
+		t.Errorf("tag go = %v, want [a]", got)
+	}
+	if got := slugs(snap.WithTag("rust", testNow)); fmt.Sprint(got) != "[b]" {
+		t.Errorf("tag rust = %v, want [b]", got)
+	}
+	if got := slugs(snap.InMonth(2024, time.February, testNow)); len(got) != 0 {
+		t.Errorf("2024-02 = %v, want none", got)
+	}
+	if got := slugs(snap.InMonth(2024, time.April, testNow)); fmt.Sprint(got) != "[b]" {
+		t.Errorf("2024-04 = %v, want [b]", got)
+	}
+	if got := fmt.Sprint(snap.Months()); got != "[2024-04 2024-03 2024-01]" {
+		t.Errorf("months = %s", got)
+	}
+	// 番号なしは番号付きの後ろ
+	if got := slugs(snap.InSeries("intro", testNow)); fmt.Sprint(got) != "[a b]" {
+		t.Errorf("series = %v, want [a b]", got)
+	}
+
+	// a を削除
+	snap = s.Update(removeOne("a"))
+	assertIndexes(t, snap)
+
+	if _, _, ok := snap.Lookup("a"); ok {
+		t.Error("removed post a still found by slug")
+	}
+	if got := snap.WithTag("go", testNow); len(got) != 0 {
+		t.Errorf("tag go = %v, want none", slugs(got))
+	}
+	if got := slugs(snap.InSeries("intro", testNow)); fmt.Sprint(got) != "[b]" {
+		t.Errorf("series = %v, want [b]", got)
+	}
+	if got := fmt.Sprint(snap.Months()); got != "[2024-04 2024-03]" {
+		t.Errorf("months = %s", got)
+	}
+	for _, hit := range snap.Search("golang", testNow) {
+		if hit.Post.Slug == "a" {
+			t.Error("removed post a still found by search")
+		}
+	}
+}
+
+// 索引のすべての位置が、その索引のキーを持つ記事を指しているか
+func assertIndexes(t *testing.T, snap *Snapshot) {
+	t.Helper()
+
+	if len(snap.bySlug) != snap.Len() {
+		t.Errorf("bySlug has %d entries for %d posts", len(snap.bySlug), snap.Len())
+	}
+	for slug, i := range snap.bySlug {
+		if snap.At(i).Slug != slug {
+			t.Errorf("bySlug[%q] points at %q", slug, snap.At(i).Slug)
+		}
+	}
+
+	count := 0
+	for month, indexes := range snap.byMonth {
+		for _, i := range indexes {
+			if got := snap.At(i).CreatedDate.Format("2006-01"); got != month {
+				t.Errorf("byMonth[%q] points at %q from %s", month, snap.At(i).Slug, got)
+			}
+		}
+		count += len(indexes)
+	}
+	if count != snap.Len() {
+		t.Errorf("byMonth covers %d of %d posts", count, snap.Len())
+	}
+
+	for tag, indexes := range snap.byTag {
+		for _, i := range indexes {
+			if !contains(snap.At(i).Tags, tag) {
+				t.Errorf("byTag[%q] points at %q tagged %v", tag, snap.At(i).Slug, snap.At(i).Tags)
+			}
+		}
+	}
+	for _, post := range snap.Posts() {
+		for _, tag := range post.Tags {
+			if !contains(slugs(snap.WithTag(tag, testNow)), post.Slug) {
+				t.Errorf("post %q missing from tag %q", post.Slug, tag)
+			}
+		}
+	}
+
+	for series, indexes := range snap.bySeries {
+		for _, i := range indexes {
+			if snap.At(i).Series != series {
+				t.Errorf("bySeries[%q] points at %q in %q", series, snap.At(i).Slug, snap.At(i).Series)
+			}
+		}
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,353 @@
+// Package images reads article image dimensions and serves resized and WebP variants.
+package images
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif" // DecodeConfig 用
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/HugoSmits86/nativewebp"
+	"golang.org/x/image/draw"
+)
+
+// Info is a source image found at load time
+type Info struct {
+	Name    string // ファイル名（例: n107c..._4ac7....png）
+	Path    string // ディスク上のパス
+	Width   int
+	Height  int
+	ModTime time.Time
+}
+
+// Variant is a resized copy of a source image in one format
+type Variant struct {
+	URL   string
+	Width int
+}
+
+// Pipeline knows the dimensions of every image in a directory and generates
+// resized PNG/JPEG and WebP variants into a cache directory on demand.
+type Pipeline struct {
+	dir        string // 元画像のディレクトリ
+	urlPrefix  string // 元画像のURL（例: /images/note）
+	cacheDir   string // 生成した画像の保存先
+	variantURL string // 生成した画像のURL（例: /images/variants）
+	widths     []int  // 生成する幅（元画像より小さいものだけ）
+
+	mu     sync.RWMutex
+	images map[string]Info // ファイル名 → 情報
+
+	genMu    sync.Mutex
+	inflight map[string]*sync.WaitGroup // 生成中のファイル（同じ画像の同時生成を防ぐ）
+}
+
+// New returns a pipeline for the images in dir, served under urlPrefix.
+// Variants are written to cacheDir and served under variantURL.
+func New(dir, urlPrefix, cacheDir, variantURL string, widths []int) *Pipeline {
+	widths = append([]int(nil), widths...)
+	sort.Ints(widths)
+	return &Pipeline{
+		dir:        dir,
+		urlPrefix:  strings.TrimSuffix(urlPrefix, "/"),
+		cacheDir:   cacheDir,
+		variantURL: strings.TrimSuffix(variantURL, "/"),
+		widths:     widths,
+		images:     map[string]Info{},
+		inflight:   map[string]*sync.WaitGroup{},
+	}
+}
+
+// Load reads the dimensions of every image in the directory (headers only).
+// Files that are not images are skipped.
+func (p *Pipeline) Load() error {
+	entries, err := os.ReadDir(p.dir)
+	if err != nil {
+		return err
+	}
+
+	images := map[string]Info{}
+	for _, entry := range entries {
+		if entry.IsDir() || formatOf(entry.Name()) == "" {
+			continue
+		}
+		path := filepath.Join(p.dir, entry.Name())
+		info, err := readInfo(path)
+		if err != nil {
+			log.Printf("⚠️ 画像を読み込めません: %s: %v", path, err)
+			continue
+		}
+		images[entry.Name()] = info
+	}
+
+	p.mu.Lock()
+	p.images = images
+	p.mu.Unlock()
+	return nil
+}
+
+func readInfo(path string) (Info, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Info{}, err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return Info{}, err
+	}
+	config, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return Info{}, err
+	}
+	return Info{
+		Name:    filepath.Base(path),
+		Path:    path,
+		Width:   config.Width,
+		Height:  config.Height,
+		ModTime: stat.ModTime(),
+	}, nil
+}
+
+// Len returns the number of images found by Load
+func (p *Pipeline) Len() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return len(p.images)
+}
+
+// Lookup finds a source image by its URL (e.g. /images/note/x.png)
+func (p *Pipeline) Lookup(url string) (Info, bool) {
+	name, ok := strings.CutPrefix(url, p.urlPrefix+"/")
+	if !ok || strings.Contains(name, "/") {
+		return Info{}, false
+	}
+	p.mu.RLock()
+	info, found := p.images[name]
+	p.mu.RUnlock()
+	return info, found
+}
+
+// Variants lists the resized copies of an image, smallest first: the original
+// format (the largest entry is the original file) and WebP.
+func (p *Pipeline) Variants(info Info) (original []Variant, webp []Variant) {
+	base, ext := splitName(info.Name)
+	for _, w := range p.widthsFor(info) {
+		webp = append(webp, Variant{URL: fmt.Sprintf("%s/%s-%d.webp", p.variantURL, base, w), Width: w})
+		if w == info.Width {
+			original = append(original, Variant{URL: p.urlPrefix + "/" + info.Name, Width: w})
+		} else {
+			original = append(original, Variant{URL: fmt.Sprintf("%s/%s-%d%s", p.variantURL, base, w, ext), Width: w})
+		}
+	}
+	return original, webp
+}
+
+// 生成する幅（元画像より小さい設定幅＋元の幅）
+func (p *Pipeline) widthsFor(info Info) []int {
+	var widths []int
+	for _, w := range p.widths {
+		if w < info.Width {
+			widths = append(widths, w)
+		}
+	}
+	return append(widths, info.Width)
+}
+
+var variantNamePattern = regexp.MustCompile(`^(.+)-(\d+)(\.[a-z]+)$`)
+
+// File returns the path of a generated variant (e.g. "x-800.webp"), generating it
+// first if it is missing or older than the source. Unknown names and widths are errors.
+func (p *Pipeline) File(name string) (string, error) {
+	m := variantNamePattern.FindStringSubmatch(name)
+	if m == nil {
+		return "", os.ErrNotExist
+	}
+	base, ext := m[1], m[3]
+	width, _ := strconv.Atoi(m[2])
+
+	info, ok := p.source(base)
+	if !ok {
+		return "", os.ErrNotExist
+	}
+	_, sourceExt := splitName(info.Name)
+	if ext != ".webp" && ext != sourceExt {
+		return "", os.ErrNotExist
+	}
+	valid := false
+	for _, w := range p.widthsFor(info) {
+		valid = valid || w == width
+	}
+	if !valid {
+		return "", os.ErrNotExist
+	}
+
+	path := filepath.Join(p.cacheDir, name)
+	if err := p.generate(info, path, width, ext); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// 拡張子を除いた名前から元画像を探す
+func (p *Pipeline) source(base string) (Info, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for name, info := range p.images {
+		if b, _ := splitName(name); b == base {
+			return info, true
+		}
+	}
+	return Info{}, false
+}
+
+// 生成済みで元画像より新しければ何もしない。同じファイルの生成は1回にまとめる
+func (p *Pipeline) generate(info Info, path string, width int, ext string) error {
+	for {
+		if stat, err := os.Stat(path); err == nil && !stat.ModTime().Before(info.ModTime) {
+			return nil
+		}
+
+		p.genMu.Lock()
+		if wg, busy := p.inflight[path]; busy {
+			p.genMu.Unlock()
+			wg.Wait()
+			continue
+		}
+		wg := &sync.WaitGroup{}
+		wg.Add(1)
+		p.inflight[path] = wg
+		p.genMu.Unlock()
+
+		err := writeVariant(info, path, width, ext)
+
+		p.genMu.Lock()
+		delete(p.inflight, path)
+		p.genMu.Unlock()
+		wg.Done()
+		return err
+	}
+}
+
+func writeVariant(info Info, path string, width int, ext string) error {
+	src, err := decode(info.Path)
+	if err != nil {
+		return err
+	}
+
+	img := src
+	if width < info.Width {
+		height := (info.Height*width + info.Width/2) / info.Width
+		if height < 1 {
+			height = 1
+		}
+		dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+		draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Src, nil)
+		img = dst
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	// 書きかけのファイルを配信しないよう一時ファイルに書いてから置き換える
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*"+ext)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := encode(tmp, img, ext); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func decode(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	return img, err
+}
+
+func encode(w io.Writer, img image.Image, ext string) error {
+	switch ext {
+	case ".webp":
+		return nativewebp.Encode(w, img, nil)
+	case ".png":
+		return png.Encode(w, img)
+	case ".jpg", ".jpeg":
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: 82})
+	}
+	return fmt.Errorf("unsupported image format: %s", ext)
+}
+
+// Warm generates every variant of every image, one at a time, so that first
+// visitors do not wait for encoding. Errors are logged and skipped.
+func (p *Pipeline) Warm() {
+	p.mu.RLock()
+	infos := make([]Info, 0, len(p.images))
+	for _, info := range p.images {
+		infos = append(infos, info)
+	}
+	p.mu.RUnlock()
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+
+	start := time.Now()
+	for _, info := range infos {
+		original, webp := p.Variants(info)
+		for _, v := range append(original, webp...) {
+			name, ok := strings.CutPrefix(v.URL, p.variantURL+"/")
+			if !ok {
+				continue // 元画像そのもの
+			}
+			if _, err := p.File(name); err != nil {
+				log.Printf("⚠️ 画像を生成できません: %s: %v", name, err)
+			}
+		}
+	}
+	log.Printf("🖼️ 画像の縮小版・WebPを準備しました: %d枚（%s）", len(infos), time.Since(start).Round(time.Second))
+}
+
+// 変換に対応する形式（拡張子で判定）
+func formatOf(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".png":
+		return "png"
+	case ".jpg", ".jpeg":
+		return "jpeg"
+	}
+	return ""
+}
+
+func splitName(name string) (base, ext string) {
+	ext = filepath.Ext(name)
+	return strings.TrimSuffix(name, ext), strings.ToLower(ext)
+}
+
+// SrcSet formats variants as a srcset attribute value ("url 400w, url 800w")
+func SrcSet(variants []Variant) string {
+	parts := make([]string, 0, len(variants))
+	for _, v := range variants {
+		parts = append(parts, fmt.Sprintf("%s %dw", v.URL, v.Width))
+	}
+	return strings.Join(parts, ", ")
+}
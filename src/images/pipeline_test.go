@@ -0,0 +1,204 @@
+package images
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/image/webp"
+)
+
+func writeImage(t *testing.T, path string, width, height int) {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if filepath.Ext(path) == ".png" {
+		err = png.Encode(f, img)
+	} else {
+		err = jpeg.Encode(f, img, nil)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// wide.png（250x125）・small.jpg（75x50）と画像でないファイルを置いたパイプライン
+func newTestPipeline(t *testing.T) *Pipeline {
+	t.Helper()
+	dir := t.TempDir()
+	writeImage(t, filepath.Join(dir, "wide.png"), 250, 125)
+	writeImage(t, filepath.Join(dir, "small.jpg"), 75, 50)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("text"), 0o644)
+	os.WriteFile(filepath.Join(dir, "broken.png"), []byte("not a png"), 0o644)
+
+	p := New(dir, "/images/note/", filepath.Join(t.TempDir(), "cache"), "/img/", []int{200, 100, 400})
+	if err := p.Load(); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestPipelineLoadAndLookup(t *testing.T) {
+	p := newTestPipeline(t)
+	if p.Len() != 2 {
+		t.Errorf("Len = %d, want 2 (text and broken files skipped)", p.Len())
+	}
+
+	info, ok := p.Lookup("/images/note/wide.png")
+	if !ok || info.Width != 250 || info.Height != 125 {
+		t.Errorf("Lookup(wide.png) = %+v, %v", info, ok)
+	}
+	for _, url := range []string{"/images/note/broken.png", "/images/note/sub/wide.png", "/images/wide.png", "/images/note/missing.png"} {
+		if _, ok := p.Lookup(url); ok {
+			t.Errorf("Lookup(%s) found an image", url)
+		}
+	}
+}
+
+func TestPipelineVariants(t *testing.T) {
+	p := newTestPipeline(t)
+
+	wide, _ := p.Lookup("/images/note/wide.png")
+	original, webpVariants := p.Variants(wide)
+	wantOriginal := []Variant{{"/img/wide-100.png", 100}, {"/img/wide-200.png", 200}, {"/images/note/wide.png", 250}}
+	wantWebP := []Variant{{"/img/wide-100.webp", 100}, {"/img/wide-200.webp", 200}, {"/img/wide-250.webp", 250}}
+	if !reflect.DeepEqual(original, wantOriginal) || !reflect.DeepEqual(webpVariants, wantWebP) {
+		t.Errorf("Variants(wide) = %v, %v", original, webpVariants)
+	}
+	if got := SrcSet(original); got != "/img/wide-100.png 100w, /img/wide-200.png 200w, /images/note/wide.png 250w" {
+		t.Errorf("SrcSet = %q", got)
+	}
+
+	// 設定幅より小さい画像は元の幅だけ
+	small, _ := p.Lookup("/images/note/small.jpg")
+	original, webpVariants = p.Variants(small)
+	if !reflect.DeepEqual(original, []Variant{{"/images/note/small.jpg", 75}}) || !reflect.DeepEqual(webpVariants, []Variant{{"/img/small-75.webp", 75}}) {
+		t.Errorf("Variants(small) = %v, %v", original, webpVariants)
+	}
+}
+
+func TestPipelineFile(t *testing.T) {
+	p := newTestPipeline(t)
+
+	tests := []struct {
+		name          string
+		width, height int
+		decode        func(*os.File) (image.Image, error)
+	}{
+		{"wide-100.png", 100, 50, func(f *os.File) (image.Image, error) { return png.Decode(f) }},
+		{"wide-200.webp", 200, 100, func(f *os.File) (image.Image, error) { return webp.Decode(f) }},
+		{"wide-250.webp", 250, 125, func(f *os.File) (image.Image, error) { return webp.Decode(f) }},
+		{"small-75.webp", 75, 50, func(f *os.File) (image.Image, error) { return webp.Decode(f) }},
+	}
+	for _, tt := range tests {
+		path, err := p.File(tt.name)
+		if err != nil {
+			t.Errorf("File(%s): %v", tt.name, err)
+			continue
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		img, err := tt.decode(f)
+		f.Close()
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if b := img.Bounds(); b.Dx() != tt.width || b.Dy() != tt.height {
+			t.Errorf("%s: %dx%d, want %dx%d", tt.name, b.Dx(), b.Dy(), tt.width, tt.height)
+		}
+	}
+}
+
+func TestPipelineFileRejectsUnknown(t *testing.T) {
+	p := newTestPipeline(t)
+	for _, name := range []string{
+		"wide-150.png",     // 設定にない幅
+		"wide-400.webp",    // 元画像より大きい
+		"wide-100.jpg",     // 元画像と違う形式
+		"missing-100.webp", // 元画像がない
+		"broken-100.webp",
+		"wide.png",
+		"../wide-100.png",
+	} {
+		if _, err := p.File(name); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("File(%s) error = %v, want ErrNotExist", name, err)
+		}
+	}
+}
+
+func TestPipelineFileRegeneratesWhenSourceChanges(t *testing.T) {
+	p := newTestPipeline(t)
+	path, err := p.File("wide-100.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	// 生成済みが元画像より新しければ作り直さない
+	info, _ := p.Lookup("/images/note/wide.png")
+	os.Chtimes(info.Path, old.Add(-time.Hour), old.Add(-time.Hour))
+	p.Load()
+	p.File("wide-100.png")
+	if stat, _ := os.Stat(path); !stat.ModTime().Equal(old) {
+		t.Error("variant regenerated although the source is older")
+	}
+
+	// 元画像が更新されたら作り直す
+	writeImage(t, info.Path, 250, 125)
+	p.Load()
+	p.File("wide-100.png")
+	if stat, _ := os.Stat(path); !stat.ModTime().After(old) {
+		t.Error("variant not regenerated after the source changed")
+	}
+}
+
+func TestPipelineFileConcurrent(t *testing.T) {
+	p := newTestPipeline(t)
+	var wg sync.WaitGroup
+	paths := make([]string, 8)
+	for i := range paths {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			path, err := p.File("wide-200.webp")
+			if err != nil {
+				t.Error(err)
+			}
+			paths[i] = path
+		}(i)
+	}
+	wg.Wait()
+	for _, path := range paths[1:] {
+		if path != paths[0] {
+			t.Errorf("paths differ: %s, %s", path, paths[0])
+		}
+	}
+	// 一時ファイルは残らない
+	entries, _ := os.ReadDir(filepath.Dir(paths[0]))
+	if len(entries) != 1 {
+		t.Errorf("cache holds %d files, want 1", len(entries))
+	}
+}
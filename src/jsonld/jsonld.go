@@ -0,0 +1,215 @@
+// Package jsonld builds schema.org structured data for page heads.
+//
+// Values are plain structs marshalled with encoding/json; Script wraps them in
+// <script type="application/ld+json"> tags for templates.
+package jsonld
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+)
+
+// Context is the @context of every top-level object
+const Context = "https://schema.org"
+
+// Script renders objects as JSON-LD script tags, one per object.
+// encoding/json escapes <, > and &, so the output cannot close the script element.
+func Script(objects ...interface{}) (template.HTML, error) {
+	var buf bytes.Buffer
+	for i, object := range objects {
+		body, err := json.MarshalIndent(object, "    ", "  ")
+		if err != nil {
+			return "", err
+		}
+		// テンプレートの {{.jsonLD}} と同じ字下げで並べる
+		if i > 0 {
+			buf.WriteString("\n    ")
+		}
+		buf.WriteString("<script type=\"application/ld+json\">\n    ")
+		buf.Write(body)
+		buf.WriteString("\n    </script>")
+	}
+	return template.HTML(buf.String()), nil
+}
+
+// ImageObject is an image with optional dimensions
+type ImageObject struct {
+	Type   string `json:"@type"`
+	URL    string `json:"url"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+}
+
+// NewImage returns an ImageObject (width and height may be 0 when unknown)
+func NewImage(url string, width, height int) *ImageObject {
+	return &ImageObject{Type: "ImageObject", URL: url, Width: width, Height: height}
+}
+
+// Person is an author
+type Person struct {
+	Type string `json:"@type"`
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+// ContactPoint is a way to contact an organization
+type ContactPoint struct {
+	Type              string `json:"@type"`
+	ContactType       string `json:"contactType"`
+	Email             string `json:"email,omitempty"`
+	URL               string `json:"url,omitempty"`
+	AvailableLanguage string `json:"availableLanguage,omitempty"`
+}
+
+// Organization is an Organization or one of its subtypes (e.g. ProfessionalService)
+type Organization struct {
+	Context         string        `json:"@context,omitempty"` // 入れ子の場合は空
+	Type            string        `json:"@type"`
+	ID              string        `json:"@id,omitempty"`
+	Name            string        `json:"name"`
+	AlternateName   string        `json:"alternateName,omitempty"`
+	Description     string        `json:"description,omitempty"`
+	URL             string        `json:"url,omitempty"`
+	Logo            *ImageObject  `json:"logo,omitempty"`
+	Image           string        `json:"image,omitempty"`
+	Email           string        `json:"email,omitempty"`
+	AreaServed      string        `json:"areaServed,omitempty"`
+	PriceRange      string        `json:"priceRange,omitempty"`
+	ContactPoint    *ContactPoint `json:"contactPoint,omitempty"`
+	HasOfferCatalog *OfferCatalog `json:"hasOfferCatalog,omitempty"`
+}
+
+// OfferCatalog lists the offers of an organization
+type OfferCatalog struct {
+	Type            string  `json:"@type"`
+	Name            string  `json:"name"`
+	ItemListElement []Offer `json:"itemListElement"`
+}
+
+// Offer is a service offered at a price
+type Offer struct {
+	Type               string              `json:"@type"`
+	ItemOffered        Service             `json:"itemOffered"`
+	PriceSpecification *PriceSpecification `json:"priceSpecification,omitempty"`
+}
+
+// Service is the thing offered
+type Service struct {
+	Type        string `json:"@type"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PriceSpecification is a fixed price (Price) or a range (MinPrice–MaxPrice)
+type PriceSpecification struct {
+	Type          string `json:"@type"` // PriceSpecification / UnitPriceSpecification
+	Price         int    `json:"price,omitempty"`
+	MinPrice      int    `json:"minPrice,omitempty"`
+	MaxPrice      int    `json:"maxPrice,omitempty"`
+	PriceCurrency string `json:"priceCurrency"`
+	UnitText      string `json:"unitText,omitempty"` // 月額など
+}
+
+// NewOffer returns an offer of a named service
+func NewOffer(name, description string, price *PriceSpecification) Offer {
+	return Offer{
+		Type:               "Offer",
+		ItemOffered:        Service{Type: "Service", Name: name, Description: description},
+		PriceSpecification: price,
+	}
+}
+
+// BlogPosting is an article
+type BlogPosting struct {
+	Context          string        `json:"@context"`
+	Type             string        `json:"@type"`
+	Headline         string        `json:"headline"`
+	Description      string        `json:"description,omitempty"`
+	URL              string        `json:"url"`
+	MainEntityOfPage string        `json:"mainEntityOfPage"`
+	DatePublished    string        `json:"datePublished"`
+	DateModified     string        `json:"dateModified"`
+	Author           *Person       `json:"author"`
+	Publisher        *Organization `json:"publisher"`
+	Image            *ImageObject  `json:"image,omitempty"`
+	WordCount        int           `json:"wordCount,omitempty"`
+	ArticleSection   string        `json:"articleSection,omitempty"`
+	Keywords         []string      `json:"keywords,omitempty"`
+	InLanguage       string        `json:"inLanguage"`
+}
+
+// Blog is the blog as a whole
+type Blog struct {
+	Context     string        `json:"@context"`
+	Type        string        `json:"@type"`
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	URL         string        `json:"url"`
+	Publisher   *Organization `json:"publisher,omitempty"`
+	InLanguage  string        `json:"inLanguage"`
+}
+
+// BreadcrumbList is the trail from the home page to the current page
+type BreadcrumbList struct {
+	Context         string     `json:"@context"`
+	Type            string     `json:"@type"`
+	ItemListElement []ListItem `json:"itemListElement"`
+}
+
+// ListItem is one step of a breadcrumb trail
+type ListItem struct {
+	Type     string `json:"@type"`
+	Position int    `json:"position"`
+	Name     string `json:"name"`
+	Item     string `json:"item,omitempty"` // 最後（現在のページ）は省略可
+}
+
+// Crumb is a breadcrumb step given to NewBreadcrumbList
+type Crumb struct {
+	Name string
+	URL  string
+}
+
+// NewBreadcrumbList numbers the crumbs from 1
+func NewBreadcrumbList(crumbs ...Crumb) *BreadcrumbList {
+	list := &BreadcrumbList{Context: Context, Type: "BreadcrumbList", ItemListElement: []ListItem{}}
+	for i, crumb := range crumbs {
+		list.ItemListElement = append(list.ItemListElement, ListItem{
+			Type:     "ListItem",
+			Position: i + 1,
+			Name:     crumb.Name,
+			Item:     crumb.URL,
+		})
+	}
+	return list
+}
+
+// FAQPage is a page of questions and answers
+type FAQPage struct {
+	Context    string     `json:"@context"`
+	Type       string     `json:"@type"`
+	MainEntity []Question `json:"mainEntity"`
+}
+
+// Question is one FAQ entry
+type Question struct {
+	Type           string `json:"@type"`
+	Name           string `json:"name"`
+	AcceptedAnswer Answer `json:"acceptedAnswer"`
+}
+
+// Answer is the answer to a Question (plain text)
+type Answer struct {
+	Type string `json:"@type"`
+	Text string `json:"text"`
+}
+
+// NewQuestion returns a question with its accepted answer
+func NewQuestion(question, answer string) Question {
+	return Question{
+		Type:           "Question",
+		Name:           question,
+		AcceptedAnswer: Answer{Type: "Answer", Text: answer},
+	}
+}
@@ -0,0 +1,93 @@
+package markdown
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// blockParsers are goldmark's default block parsers (same priorities), changed
+// to read articles the way blackfriday did: lists and blockquotes need a blank
+// line before them (articles write "手順：\n- a\n- b" expecting one paragraph),
+// and a code fence without a closing fence is plain text instead of a code
+// block running to the end of the article.
+func blockParsers() []util.PrioritizedValue {
+	return []util.PrioritizedValue{
+		util.Prioritized(parser.NewSetextHeadingParser(), 100),
+		util.Prioritized(parser.NewThematicBreakParser(), 200),
+		util.Prioritized(noInterruptParser{parser.NewListParser()}, 300),
+		util.Prioritized(parser.NewListItemParser(), 400),
+		util.Prioritized(parser.NewCodeBlockParser(), 500),
+		util.Prioritized(parser.NewATXHeadingParser(), 600),
+		util.Prioritized(closedFenceParser{parser.NewFencedCodeBlockParser()}, 700),
+		util.Prioritized(noInterruptParser{parser.NewBlockquoteParser()}, 800),
+		util.Prioritized(parser.NewHTMLBlockParser(), 900),
+		util.Prioritized(parser.NewParagraphParser(), 1000),
+	}
+}
+
+// noInterruptParser does not open its block right after a paragraph line.
+// Inside a list item a nested list still may, as blackfriday allowed.
+type noInterruptParser struct {
+	parser.BlockParser
+}
+
+func (p noInterruptParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	if last := pc.LastOpenedBlock().Node; ast.IsParagraph(last) && last.Parent() == parent && parent.Kind() != ast.KindListItem {
+		return nil, parser.NoChildren
+	}
+	return p.BlockParser.Open(parent, reader, pc)
+}
+
+// closedFenceParser opens a fenced code block only if a closing fence follows
+type closedFenceParser struct {
+	parser.BlockParser
+}
+
+func (p closedFenceParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	fence := fenceOf(line)
+	if fence == nil {
+		return nil, parser.NoChildren
+	}
+	rest := reader.Source()[segment.Stop:]
+	for len(rest) > 0 {
+		next := rest
+		if i := bytes.IndexByte(rest, '\n'); i >= 0 {
+			next, rest = rest[:i+1], rest[i+1:]
+		} else {
+			rest = nil
+		}
+		// 引用・リスト内の閉じフェンスは行頭の > と空白を除いて探す
+		closing := fenceOf(bytes.TrimLeft(next, " \t>"))
+		if closing != nil && closing[0] == fence[0] && len(closing) >= len(fence) && isBlankAfter(next, closing) {
+			return p.BlockParser.Open(parent, reader, pc)
+		}
+	}
+	return nil, parser.NoChildren
+}
+
+// 行頭（インデント3以内）の ``` や ~~~ の部分
+func fenceOf(line []byte) []byte {
+	trimmed := bytes.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 || len(trimmed) == 0 || (trimmed[0] != '`' && trimmed[0] != '~') {
+		return nil
+	}
+	n := 0
+	for n < len(trimmed) && trimmed[n] == trimmed[0] {
+		n++
+	}
+	if n < 3 {
+		return nil
+	}
+	return trimmed[:n]
+}
+
+// 閉じフェンスの後ろが空白だけか
+func isBlankAfter(line, fence []byte) bool {
+	i := bytes.Index(line, fence)
+	return len(bytes.TrimSpace(line[i+len(fence):])) == 0
+}
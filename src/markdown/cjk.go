@@ -0,0 +1,138 @@
+package markdown
+
+import (
+	"bytes"
+	"regexp"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// cjkEmphasisParser parses "*" emphasis like CommonMark, but also lets a
+// delimiter run open or close next to Japanese text. Strict CommonMark rejects
+// "これは**「重要」**です" because the "**" sit between a letter and punctuation;
+// blackfriday accepted it and many articles rely on that.
+type cjkEmphasisParser struct{}
+
+func (cjkEmphasisParser) Trigger() []byte {
+	return []byte{'*'}
+}
+
+func (cjkEmphasisParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	before := block.PrecendingCharacter()
+	line, segment := block.PeekLine()
+
+	n := 0
+	for n < len(line) && line[n] == '*' {
+		n++
+	}
+	if n == 0 {
+		return nil
+	}
+	after := ' '
+	if n < len(line) {
+		after = util.ToRune(line, n)
+	}
+
+	beforeSpace, afterSpace := util.IsSpaceRune(before), util.IsSpaceRune(after)
+	beforePunct, afterPunct := util.IsPunctRune(before), util.IsPunctRune(after)
+	cjk := isCJKRune(before) || isCJKRune(after)
+
+	canOpen := !afterSpace && (!afterPunct || beforeSpace || beforePunct || cjk)
+	canClose := !beforeSpace && (!beforePunct || afterSpace || afterPunct || cjk)
+
+	node := parser.NewDelimiter(canOpen, canClose, n, '*', emphasisProcessor{})
+	node.Segment = segment.WithStop(segment.Start + node.OriginalLength)
+	block.Advance(node.OriginalLength)
+	pc.PushDelimiter(node)
+	return node
+}
+
+type emphasisProcessor struct{}
+
+func (emphasisProcessor) IsDelimiter(b byte) bool {
+	return b == '*'
+}
+
+func (emphasisProcessor) CanOpenCloser(opener, closer *parser.Delimiter) bool {
+	return opener.Char == closer.Char
+}
+
+func (emphasisProcessor) OnMatch(consumes int) ast.Node {
+	return ast.NewEmphasis(consumes)
+}
+
+// cjkURLPattern is goldmark's Linkify URL pattern (ASCII only, so the URL
+// ends at the first Japanese character)
+var cjkURLPattern = regexp.MustCompile(`^https?://[-a-zA-Z0-9@:%._\+~#=]{1,256}\.[a-z]+(?::\d+)?(?:[/#?][-a-zA-Z0-9@:%_+.~#$!?&/=\(\);,'">\^{}\[\]` + "`" + `]*)?`)
+
+// linkifyCJK links URLs written right after Japanese text, as in
+// "公式サイト（https://example.com）の手順". goldmark's Linkify only starts
+// after a space or "(", and blackfriday took the Japanese text after the URL
+// into the link.
+func linkifyCJK(doc *ast.Document, source []byte) {
+	var texts []*ast.Text
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n.Kind() {
+		case ast.KindLink, ast.KindAutoLink, ast.KindCodeSpan, ast.KindImage:
+			return ast.WalkSkipChildren, nil
+		}
+		if t, ok := n.(*ast.Text); ok && !t.IsRaw() {
+			texts = append(texts, t)
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, t := range texts {
+		for t != nil {
+			t = linkifyCJKText(t, source)
+		}
+	}
+}
+
+// t の中の最初のURLをリンクにし、残りのテキスト（なければ nil）を返す
+func linkifyCJKText(t *ast.Text, source []byte) *ast.Text {
+	value := t.Segment.Value(source)
+	for offset := 0; offset < len(value); {
+		i := bytes.Index(value[offset:], []byte("http"))
+		if i < 0 {
+			return nil
+		}
+		i += offset
+		offset = i + 1
+		before, _ := utf8.DecodeLastRune(value[:i])
+		m := cjkURLPattern.Find(value[i:])
+		if i == 0 || !isCJKRune(before) || m == nil {
+			continue
+		}
+		end := i + len(bytes.TrimRight(m, "?!.,:*_~"))
+
+		parent := t.Parent()
+		head := ast.NewTextSegment(t.Segment.WithStop(t.Segment.Start + i))
+		link := ast.NewAutoLink(ast.AutoLinkURL, ast.NewTextSegment(text.NewSegment(t.Segment.Start+i, t.Segment.Start+end)))
+		parent.InsertBefore(parent, t, head)
+		parent.InsertBefore(parent, t, link)
+		if t.Segment.Start+end == t.Segment.Stop && !t.SoftLineBreak() && !t.HardLineBreak() {
+			parent.RemoveChild(parent, t)
+			return nil
+		}
+		// 残り（改行の指定は残りのテキストが引き継ぐ）
+		t.Segment = t.Segment.WithStart(t.Segment.Start + end)
+		return t
+	}
+	return nil
+}
+
+// 日本語・中国語・韓国語の文字と全角の記号
+func isCJKRune(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) ||
+		(r >= 0x3000 && r <= 0x303F) || // 句読点・括弧
+		(r >= 0xFF00 && r <= 0xFFEF) // 全角英数・記号
+}
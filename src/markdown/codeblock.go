@@ -0,0 +1,53 @@
+package markdown
+
+import (
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// codeBlockRenderer renders fenced and indented code blocks with WriteCodeBlock,
+// except mermaid fences which become client-rendered diagrams
+type codeBlockRenderer struct{}
+
+func (codeBlockRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindFencedCodeBlock, renderCodeBlock)
+	reg.Register(ast.KindCodeBlock, renderCodeBlock)
+}
+
+func renderCodeBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	var info string
+	if fenced, ok := node.(*ast.FencedCodeBlock); ok && fenced.Info != nil {
+		info = string(fenced.Info.Segment.Value(source))
+	}
+
+	var code []byte
+	lines := node.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		line := lines.At(i)
+		code = append(code, line.Value(source)...)
+	}
+
+	if ParseFenceInfo(info).Language == "mermaid" {
+		writeMermaid(w, code)
+	} else {
+		WriteCodeBlock(w, info, code)
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+// writeMermaid writes a diagram container rendered by mermaid.js in the browser (see main.js).
+// The <pre> holds the escaped source, so the diagram definition stays readable
+// without JavaScript, in feeds and if the script fails to load.
+func writeMermaid(w util.BufWriter, code []byte) {
+	w.WriteString(`<div class="diagram" data-diagram="mermaid">`)
+	w.WriteString(`<pre class="mermaid">`)
+	w.Write(util.EscapeHTML(code))
+	w.WriteString("</pre>")
+	w.WriteString(`<noscript><p class="diagram-note">図を表示するにはJavaScriptを有効にしてください（上記は図の定義です）</p></noscript>`)
+	w.WriteString("</div>\n")
+}
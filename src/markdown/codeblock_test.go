@@ -0,0 +1,37 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMermaid(t *testing.T) {
+	source := "```mermaid\ngraph TD\n  A[\"<script>\"] --> B & C\n```\n"
+	html := string(New().Render([]byte(source)).HTML)
+
+	// 定義はエスケープして <pre class="mermaid"> に残す（ハイライトしない）
+	want := `<div class="diagram" data-diagram="mermaid"><pre class="mermaid">graph TD
+  A[&quot;&lt;script&gt;&quot;] --&gt; B &amp; C
+</pre><noscript>`
+	if !strings.HasPrefix(html, want) {
+		t.Errorf("got\n%s\nwant prefix\n%s", html, want)
+	}
+	if strings.Contains(html, "code-block") || strings.Contains(html, "chroma") {
+		t.Errorf("mermaid block highlighted as code:\n%s", html)
+	}
+}
+
+func TestRenderMermaidInfoVariants(t *testing.T) {
+	for _, info := range []string{"Mermaid", "language-mermaid", "mermaid {title=\"flow\"}"} {
+		html := string(New().Render([]byte("```" + info + "\ngraph LR\n  A --> B\n```\n")).HTML)
+		if !strings.Contains(html, `<pre class="mermaid">graph LR`) {
+			t.Errorf("info %q not rendered as a diagram:\n%s", info, html)
+		}
+	}
+	// 他の言語・インデントのコードブロックは図にしない
+	for _, source := range []string{"```mermaid-like\ngraph\n```\n", "    graph TD\n"} {
+		if html := string(New().Render([]byte(source)).HTML); strings.Contains(html, "diagram") {
+			t.Errorf("%q rendered as a diagram:\n%s", source, html)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package markdown_test
+
+import (
+	"bytes"
+	"flag"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+	"infohiroki-go/src/markdown"
+	"infohiroki-go/src/models"
+)
+
+// go test ./src/markdown -run Golden -update で現在の出力を正とする
+var update = flag.Bool("update", false, "rewrite testdata/golden from the current renderer")
+
+// 比べない属性（画像の遅延読み込み・レスポンシブ対応は移行後に加えたもの）
+var ignoredAttributes = map[string]bool{
+	"loading": true, "decoding": true, "width": true, "height": true, "srcset": true, "sizes": true,
+}
+
+// TestGolden renders every article and compares it with testdata/golden, which
+// holds the output of the previous renderer (blackfriday) in normalized form.
+// Normalizing drops what does not change how a page reads: whitespace between
+// tags, entity spelling, percent-encoding in URLs (goldmark encodes "#見出し"
+// links, blackfriday did not), <br> vs <br />, attribute order and image
+// loading attributes.
+// Intentional differences are listed in testdata/golden/README.
+func TestGolden(t *testing.T) {
+	files, err := filepath.Glob("../../articles/*.md")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no articles: %v", err)
+	}
+	renderer := markdown.New()
+
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".md")
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, body, _ := models.ParseFrontMatter(string(data))
+			got := normalizeHTML(renderer.Render([]byte(body)).HTML)
+
+			golden := filepath.Join("testdata", "golden", name+".txt")
+			if *update {
+				if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v (run with -update for a new article)", err)
+			}
+			if diff := firstDiff(string(want), got); diff != "" {
+				t.Errorf("output differs from %s:\n%s", golden, diff)
+			}
+		})
+	}
+}
+
+// HTMLを1行1トークンのテキストにする。コードブロックは言語と中身のテキストだけを残す
+func normalizeHTML(source []byte) string {
+	var out []string
+	var text strings.Builder
+	flushText := func() {
+		if s := strings.Join(strings.Fields(text.String()), " "); s != "" {
+			out = append(out, "text "+s)
+		}
+		text.Reset()
+	}
+
+	z := html.NewTokenizer(bytes.NewReader(source))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		token := z.Token()
+		switch tt {
+		case html.TextToken:
+			text.WriteString(token.Data)
+			continue
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if block, ok := codeBlock(token); ok {
+				flushText()
+				out = append(out, "code "+block+" "+strings.Join(strings.Fields(skipElement(z, token.Data)), " "))
+				continue
+			}
+			if token.Data == "picture" || token.Data == "source" {
+				continue
+			}
+			flushText()
+			out = append(out, "<"+token.Data+attributes(token)+">")
+		case html.EndTagToken:
+			if token.Data == "picture" {
+				continue
+			}
+			flushText()
+			out = append(out, "</"+token.Data+">")
+		}
+	}
+	flushText()
+	return strings.Join(out, "\n") + "\n"
+}
+
+// ハイライト済みのコードブロック・Mermaidの図（どちらも言語名を返す）
+func codeBlock(token html.Token) (string, bool) {
+	if token.Data != "div" {
+		return "", false
+	}
+	var class, lang string
+	for _, attr := range token.Attr {
+		switch attr.Key {
+		case "class":
+			class = attr.Val
+		case "data-lang", "data-diagram":
+			lang = attr.Val
+		}
+	}
+	if class != "code-block" && class != "diagram" {
+		return "", false
+	}
+	if lang == "" {
+		lang = "-"
+	}
+	return lang, true
+}
+
+// 要素の終わりまで読み進め、中のテキストを返す（<noscript> の注記は除く）
+func skipElement(z *html.Tokenizer, tag string) string {
+	var text strings.Builder
+	depth, noscript := 1, 0
+	for depth > 0 {
+		switch z.Next() {
+		case html.ErrorToken:
+			return text.String()
+		case html.StartTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case tag:
+				depth++
+			case "noscript":
+				noscript++
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case tag:
+				depth--
+			case "noscript":
+				noscript--
+			}
+		case html.TextToken:
+			if noscript == 0 {
+				text.WriteString(z.Token().Data)
+			}
+		}
+	}
+	return text.String()
+}
+
+func attributes(token html.Token) string {
+	var attrs []string
+	for _, attr := range token.Attr {
+		if ignoredAttributes[attr.Key] {
+			continue
+		}
+		value := attr.Val
+		if attr.Key == "href" || attr.Key == "src" {
+			if unescaped, err := url.PathUnescape(value); err == nil {
+				value = unescaped
+			}
+		}
+		attrs = append(attrs, attr.Key+"="+value)
+	}
+	if len(attrs) == 0 {
+		return ""
+	}
+	sort.Strings(attrs)
+	return " " + strings.Join(attrs, " ")
+}
+
+// 最初に異なる行の前後
+func firstDiff(want, got string) string {
+	wantLines, gotLines := strings.Split(want, "\n"), strings.Split(got, "\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			return "line " + strconv.Itoa(i+1) + ":\n- " + w + "\n+ " + g
+		}
+	}
+	return ""
+}
@@ -0,0 +1,157 @@
+package markdown
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Option configures New
+type Option func(*goldmarkRenderer)
+
+// WithTransformers registers plugin transformers, run after the built-in ones
+func WithTransformers(transformers ...Transformer) Option {
+	return func(r *goldmarkRenderer) {
+		r.transformers = append(r.transformers, transformers...)
+	}
+}
+
+// WithLinkRewriter rewrites the destination of every link (see RewriteLinks)
+func WithLinkRewriter(rewrite func(dest string) string) Option {
+	return WithTransformers(RewriteLinks(rewrite))
+}
+
+// goldmarkRenderer is the CommonMark/GFM renderer. It is safe for concurrent use.
+type goldmarkRenderer struct {
+	md           goldmark.Markdown
+	transformers []Transformer
+	resolveImage ImageResolver
+}
+
+// New returns a CommonMark/GFM renderer with footnotes, definition lists, task lists,
+// heading attributes ({#id .class}), callouts, highlighted code blocks and lazy-loaded images.
+// Block structure and typography (SmartyPants) follow blackfriday, the previous renderer,
+// so articles render as before; testdata/golden checks that.
+func New(options ...Option) Renderer {
+	r := &goldmarkRenderer{
+		transformers: []Transformer{TransformerFunc(transformCallouts), TransformerFunc(linkifyCJK)},
+	}
+	for _, option := range options {
+		option(r)
+	}
+	r.md = goldmark.New(
+		goldmark.WithParser(parser.NewParser(
+			parser.WithBlockParsers(blockParsers()...),
+			parser.WithInlineParsers(parser.DefaultInlineParsers()...),
+			parser.WithParagraphTransformers(parser.DefaultParagraphTransformers()...),
+		)),
+		goldmark.WithExtensions(
+			extension.NewTable(extension.WithTableCellAlignMethod(extension.TableCellAlignAttribute)),
+			extension.Strikethrough,
+			extension.Linkify,
+			extension.TaskList,
+			extension.Footnote,
+			extension.DefinitionList,
+		),
+		goldmark.WithParserOptions(
+			parser.WithAutoHeadingID(),
+			parser.WithAttribute(),
+			parser.WithInlineParsers(util.Prioritized(cjkEmphasisParser{}, 499)),
+		),
+		goldmark.WithRendererOptions(
+			html.WithUnsafe(), // 記事中の生HTMLはそのまま出力する
+			html.WithXHTML(),
+			renderer.WithNodeRenderers(
+				util.Prioritized(codeBlockRenderer{}, 100),
+				util.Prioritized(imageRenderer{resolve: r.resolveImage}, 100),
+			),
+		),
+	)
+	return r
+}
+
+func (r *goldmarkRenderer) Render(source []byte) Result {
+	// 見出しIDは従来（blackfriday）と同じ規則で生成し、記事内リンクを壊さない
+	pc := parser.NewContext(parser.WithIDs(newHeadingIDs()))
+	doc := r.md.Parser().Parse(text.NewReader(source), parser.WithContext(pc)).(*ast.Document)
+
+	for _, t := range r.transformers {
+		t.Transform(doc, source)
+	}
+
+	result := Result{}
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.Heading:
+			id, _ := node.AttributeString("id")
+			if id, ok := id.([]byte); ok && len(id) > 0 {
+				result.Headings = append(result.Headings, Heading{
+					Level: node.Level,
+					ID:    string(id),
+					Text:  nodeText(node, source),
+				})
+			} else {
+				// 記号だけの見出しなどIDが空の場合は id="" を出力しない
+				removeAttribute(node, "id")
+			}
+		case *ast.Image:
+			result.Images = append(result.Images, string(node.Destination))
+		}
+		return ast.WalkContinue, nil
+	})
+	// 目次用の見出しテキストを取り出した後に適用（置き換えた部分はエスケープ済みの文字列になる）
+	applySmartypants(doc, source)
+
+	var buf bytes.Buffer
+	if err := r.md.Renderer().Render(&buf, source, doc); err != nil {
+		// bytes.Buffer への書き込みは失敗しないが、念のため途中までの出力は捨てる
+		buf.Reset()
+		buf.WriteString("<pre>")
+		buf.Write(util.EscapeHTML(source))
+		buf.WriteString("</pre>")
+	}
+	result.HTML = buf.Bytes()
+	return result
+}
+
+func removeAttribute(node ast.Node, name string) {
+	attrs := node.Attributes()
+	node.RemoveAttributes()
+	for _, attr := range attrs {
+		if string(attr.Name) != name {
+			node.SetAttribute(attr.Name, attr.Value)
+		}
+	}
+}
+
+// ノード内のテキスト（強調・リンク・コードを含む）を連結
+func nodeText(node ast.Node, source []byte) string {
+	var buf strings.Builder
+	ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch t := n.(type) {
+		case *ast.Text:
+			buf.Write(t.Segment.Value(source))
+			if t.SoftLineBreak() {
+				buf.WriteByte(' ')
+			}
+		case *ast.String:
+			buf.Write(t.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.TrimSpace(buf.String())
+}
@@ -0,0 +1,197 @@
+package markdown
+
+import (
+	"bytes"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/alecthomas/chroma/v2"
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+)
+
+// FenceOptions are the settings parsed from a fenced code block's info string,
+// e.g. "go {linenos=true hl_lines="2 4-6" linenostart=10}"
+type FenceOptions struct {
+	Language    string
+	LineNumbers bool
+	LineStart   int
+	Highlight   [][2]int // 強調する行の範囲（ブロック先頭を1とする行番号、両端を含む）
+}
+
+// ParseFenceInfo parses a fence info string into its language and attributes.
+// Unknown attributes are ignored.
+func ParseFenceInfo(info string) FenceOptions {
+	opts := FenceOptions{LineStart: 1}
+
+	fields := splitFenceInfo(info)
+	if len(fields) > 0 && !strings.Contains(fields[0], "=") && !isLineRanges(fields[0]) {
+		opts.Language = normalizeLanguage(fields[0])
+		fields = fields[1:]
+	}
+
+	for _, field := range fields {
+		key, value, hasValue := strings.Cut(field, "=")
+		value = strings.Trim(value, `"'`)
+		switch strings.ToLower(key) {
+		case "linenos", "line-numbers", "linenumbers":
+			opts.LineNumbers = !hasValue || (value != "false" && value != "0")
+		case "linenostart", "start":
+			if n, err := strconv.Atoi(value); err == nil && n > 0 {
+				opts.LineStart = n
+				opts.LineNumbers = true
+			}
+		case "hl_lines", "hl", "highlight":
+			opts.Highlight = append(opts.Highlight, parseLineRanges(value)...)
+		default:
+			// {3-5} のような行範囲のみの指定
+			if !hasValue && isLineRanges(key) {
+				opts.Highlight = append(opts.Highlight, parseLineRanges(key)...)
+			}
+		}
+	}
+	return opts
+}
+
+// WriteCodeBlock writes a highlighted code block using CSS classes (see style.css).
+// The wrapper's data attributes are the hook for the copy button in main.js.
+func WriteCodeBlock(w io.Writer, info string, code []byte) {
+	opts := ParseFenceInfo(info)
+
+	io.WriteString(w, `<div class="code-block"`)
+	if opts.Language != "" {
+		io.WriteString(w, ` data-lang="`+html.EscapeString(opts.Language)+`"`)
+	}
+	io.WriteString(w, ">")
+
+	var buf bytes.Buffer
+	if err := highlight(&buf, opts, string(code)); err != nil {
+		// ハイライトに失敗した場合はエスケープしたテキストをそのまま出力
+		buf.Reset()
+		buf.WriteString(`<pre class="chroma"><code>`)
+		buf.WriteString(html.EscapeString(string(code)))
+		buf.WriteString("</code></pre>")
+	}
+	w.Write(buf.Bytes())
+
+	io.WriteString(w, "</div>\n")
+}
+
+func highlight(w io.Writer, opts FenceOptions, code string) error {
+	iterator, err := lexerFor(opts.Language).Tokenise(nil, code)
+	if err != nil {
+		return err
+	}
+
+	// chromaは表示上の行番号で判定するため開始番号の分ずらす
+	ranges := make([][2]int, len(opts.Highlight))
+	for i, r := range opts.Highlight {
+		ranges[i] = [2]int{r[0] + opts.LineStart - 1, r[1] + opts.LineStart - 1}
+	}
+
+	formatter := chromahtml.New(
+		chromahtml.WithClasses(true),
+		chromahtml.WithLineNumbers(opts.LineNumbers),
+		chromahtml.BaseLineNumber(opts.LineStart),
+		chromahtml.HighlightLines(ranges),
+		chromahtml.TabWidth(4),
+	)
+	return formatter.Format(w, styles.Fallback, iterator)
+}
+
+// 言語名ごとのレキサー（未対応の言語の検索は遅いため結果を覚えておく）
+var lexerCache sync.Map
+
+// 言語指定がない・未対応の言語はプレーンテキストとして扱う（推測はしない）
+func lexerFor(language string) chroma.Lexer {
+	if language == "" {
+		return lexers.Fallback
+	}
+	if lexer, ok := lexerCache.Load(language); ok {
+		return lexer.(chroma.Lexer)
+	}
+	lexer := lexers.Get(language)
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	lexer = chroma.Coalesce(lexer)
+	lexerCache.Store(language, lexer)
+	return lexer
+}
+
+// 情報文字列を空白区切りで分割（{}は外し、引用符内の空白は区切らない）
+func splitFenceInfo(info string) []string {
+	info = strings.NewReplacer("{", " ", "}", " ", ",", " ").Replace(info)
+
+	var fields []string
+	var current strings.Builder
+	var quote rune
+	for _, r := range info {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+			current.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote = r
+			current.WriteRune(r)
+		case r == ' ' || r == '\t':
+			if current.Len() > 0 {
+				fields = append(fields, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		fields = append(fields, current.String())
+	}
+	return fields
+}
+
+// "go" / ".go" / "language-go" を "go" に揃える
+func normalizeLanguage(lang string) string {
+	lang = strings.TrimPrefix(lang, ".")
+	lang = strings.TrimPrefix(lang, "language-")
+	return strings.ToLower(lang)
+}
+
+// "2 4-6" や "2;4-6" を行範囲に変換（不正な要素は無視）
+func parseLineRanges(value string) [][2]int {
+	var ranges [][2]int
+	for _, part := range strings.FieldsFunc(value, func(r rune) bool {
+		return r == ' ' || r == ';' || r == ','
+	}) {
+		from, to, isRange := strings.Cut(part, "-")
+		start, err := strconv.Atoi(from)
+		if err != nil || start < 1 {
+			continue
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.Atoi(to); err != nil || end < start {
+				continue
+			}
+		}
+		ranges = append(ranges, [2]int{start, end})
+	}
+	return ranges
+}
+
+func isLineRanges(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, r := range value {
+		if (r < '0' || r > '9') && r != '-' && r != ';' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,78 @@
+package markdown
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseFenceInfo(t *testing.T) {
+	tests := []struct {
+		info string
+		want FenceOptions
+	}{
+		{"", FenceOptions{LineStart: 1}},
+		{"go", FenceOptions{Language: "go", LineStart: 1}},
+		{"language-Python", FenceOptions{Language: "python", LineStart: 1}},
+		{".js", FenceOptions{Language: "js", LineStart: 1}},
+		{"go {linenos=true}", FenceOptions{Language: "go", LineNumbers: true, LineStart: 1}},
+		{"go {linenos=false}", FenceOptions{Language: "go", LineStart: 1}},
+		{"go linenostart=10", FenceOptions{Language: "go", LineNumbers: true, LineStart: 10}},
+		{`go {hl_lines="2 4-6"}`, FenceOptions{Language: "go", LineStart: 1, Highlight: [][2]int{{2, 2}, {4, 6}}}},
+		{"go {3-5}", FenceOptions{Language: "go", LineStart: 1, Highlight: [][2]int{{3, 5}}}},
+		{"{1,3}", FenceOptions{LineStart: 1, Highlight: [][2]int{{1, 1}, {3, 3}}}},
+		// 不正な範囲・未知の属性は無視
+		{`go {hl_lines="0 5-2 x 7" title="a b" linenostart=-1}`, FenceOptions{Language: "go", LineStart: 1, Highlight: [][2]int{{7, 7}}}},
+	}
+	for _, tt := range tests {
+		if got := ParseFenceInfo(tt.info); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseFenceInfo(%q) = %+v, want %+v", tt.info, got, tt.want)
+		}
+	}
+}
+
+func TestWriteCodeBlock(t *testing.T) {
+	var buf bytes.Buffer
+	WriteCodeBlock(&buf, `go {linenos=true hl_lines="2" linenostart=10}`, []byte("package main\nfunc main() {}\n"))
+	out := buf.String()
+
+	for _, want := range []string{
+		`<div class="code-block" data-lang="go"><pre class="chroma">`,
+		`<span class="kn">package</span>`,
+		`<span class="ln">10</span>`,
+		// 強調はブロック内の2行目（表示上は11行目）
+		`<span class="line hl"><span class="ln">11</span>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %s in\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "style=") {
+		t.Errorf("inline styles in\n%s", out)
+	}
+}
+
+func TestWriteCodeBlockEscapes(t *testing.T) {
+	for _, info := range []string{"", "nosuchlang", `"><script>`} {
+		var buf bytes.Buffer
+		WriteCodeBlock(&buf, info, []byte("<script>alert(1)</script>&\n"))
+		out := buf.String()
+		if strings.Contains(out, "<script>") {
+			t.Errorf("info %q: unescaped code in\n%s", info, out)
+		}
+		if !strings.Contains(out, "&lt;script&gt;alert(1)&lt;/script&gt;&amp;") {
+			t.Errorf("info %q: code missing in\n%s", info, out)
+		}
+	}
+}
+
+func TestRenderCodeBlocks(t *testing.T) {
+	html := string(New().Render([]byte("```go\nx := 1\n```\n\n    indented <b>\n")).HTML)
+	if !strings.Contains(html, `<div class="code-block" data-lang="go">`) {
+		t.Errorf("fenced block not highlighted:\n%s", html)
+	}
+	if !strings.Contains(html, `<div class="code-block"><pre class="chroma"><code><span class="line"><span class="cl">indented &lt;b&gt;`) {
+		t.Errorf("indented block not rendered as plain code:\n%s", html)
+	}
+}
@@ -0,0 +1,66 @@
+package markdown
+
+import (
+	"fmt"
+	"unicode"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+)
+
+// headingIDs generates heading ids the way blackfriday's AutoHeadingIDs did,
+// so anchors in existing articles (and links to them) keep working:
+// letters and digits are kept (lowercased), other runs become a single "-",
+// and duplicates get "-1", "-2", ... suffixes.
+type headingIDs struct {
+	used map[string]int
+}
+
+func newHeadingIDs() parser.IDs {
+	return &headingIDs{used: map[string]int{}}
+}
+
+func (s *headingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	id := sanitizedAnchorName(string(value))
+	if id == "" {
+		return nil
+	}
+	return []byte(s.unique(id))
+}
+
+func (s *headingIDs) Put(value []byte) {
+	s.used[string(value)] = 0
+}
+
+func (s *headingIDs) unique(id string) string {
+	for count, found := s.used[id]; found; count, found = s.used[id] {
+		candidate := fmt.Sprintf("%s-%d", id, count+1)
+		if _, taken := s.used[candidate]; !taken {
+			s.used[id] = count + 1
+			id = candidate
+		} else {
+			id = id + "-1"
+		}
+	}
+	if _, found := s.used[id]; !found {
+		s.used[id] = 0
+	}
+	return id
+}
+
+func sanitizedAnchorName(text string) string {
+	var anchor []rune
+	dash := false
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			if dash && len(anchor) > 0 {
+				anchor = append(anchor, '-')
+			}
+			dash = false
+			anchor = append(anchor, unicode.ToLower(r))
+		} else {
+			dash = true
+		}
+	}
+	return string(anchor)
+}
@@ -0,0 +1,85 @@
+package markdown
+
+import (
+	"fmt"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// ImageSizes is the sizes attribute for article images (本文の最大幅は約800px)
+const ImageSizes = "(max-width: 800px) 100vw, 800px"
+
+// ResponsiveImage describes the stored variants of an image
+type ResponsiveImage struct {
+	Width, Height int
+	SrcSet        string // 元の形式の縮小版（"url 400w, url 800w"）
+	WebPSrcSet    string // WebP版
+}
+
+// ImageResolver returns the variants of an image URL, or false if it has none
+type ImageResolver func(src string) (ResponsiveImage, bool)
+
+// WithImageResolver renders known images as <picture> with srcset, sizes and
+// explicit dimensions. All images are lazy-loaded with or without a resolver.
+func WithImageResolver(resolve ImageResolver) Option {
+	return func(r *goldmarkRenderer) {
+		r.resolveImage = resolve
+	}
+}
+
+// imageRenderer renders images with loading="lazy" and, when resolved, responsive variants
+type imageRenderer struct {
+	resolve ImageResolver
+}
+
+func (r imageRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindImage, r.renderImage)
+}
+
+func (r imageRenderer) renderImage(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.Image)
+	src := string(n.Destination)
+
+	var image ResponsiveImage
+	resolved := false
+	if r.resolve != nil {
+		image, resolved = r.resolve(src)
+	}
+
+	if resolved && image.WebPSrcSet != "" {
+		w.WriteString(`<picture><source type="image/webp" srcset="`)
+		w.Write(util.EscapeHTML([]byte(image.WebPSrcSet)))
+		w.WriteString(`" sizes="` + ImageSizes + `" />`)
+	}
+
+	w.WriteString(`<img src="`)
+	w.Write(util.EscapeHTML(util.URLEscape(n.Destination, true)))
+	w.WriteString(`" alt="`)
+	w.Write(util.EscapeHTML([]byte(nodeText(n, source))))
+	w.WriteString(`"`)
+	if n.Title != nil {
+		w.WriteString(` title="`)
+		w.Write(util.EscapeHTML(n.Title))
+		w.WriteString(`"`)
+	}
+	if resolved {
+		if image.SrcSet != "" {
+			w.WriteString(` srcset="`)
+			w.Write(util.EscapeHTML([]byte(image.SrcSet)))
+			w.WriteString(`" sizes="` + ImageSizes + `"`)
+		}
+		fmt.Fprintf(w, ` width="%d" height="%d"`, image.Width, image.Height)
+	}
+	w.WriteString(` loading="lazy" decoding="async" />`)
+
+	if resolved && image.WebPSrcSet != "" {
+		w.WriteString(`</picture>`)
+	}
+	// 画像はリンクや強調を含まないので子ノード（altテキスト）は出力しない
+	return ast.WalkSkipChildren, nil
+}
@@ -0,0 +1,53 @@
+// Package markdown converts article markdown to HTML.
+//
+// Rendering sits behind the Renderer interface. The default implementation is
+// CommonMark/GFM compliant (goldmark) and can be extended with Transformers,
+// which edit the parsed document before it is rendered.
+package markdown
+
+import (
+	"github.com/yuin/goldmark/ast"
+)
+
+// Heading is a heading found while rendering, in document order
+type Heading struct {
+	Level int
+	ID    string // 出力HTMLのid（重複時の連番付き）
+	Text  string
+}
+
+// Result is the output of Render
+type Result struct {
+	HTML     []byte
+	Headings []Heading
+	Images   []string // 画像のURL（出現順）
+}
+
+// Renderer converts markdown to HTML
+type Renderer interface {
+	Render(source []byte) Result
+}
+
+// Transformer is a plugin hook: it may rewrite the parsed document before rendering.
+// Transformers run in the order they were registered and must not keep references
+// to the document after returning.
+type Transformer interface {
+	Transform(doc *ast.Document, source []byte)
+}
+
+// TransformerFunc adapts a function to a Transformer
+type TransformerFunc func(doc *ast.Document, source []byte)
+
+// Transform calls f(doc, source)
+func (f TransformerFunc) Transform(doc *ast.Document, source []byte) {
+	f(doc, source)
+}
+
+// Default is the renderer used by Render. Replace it before content is loaded
+// to register site-specific transformers.
+var Default Renderer = New()
+
+// Render converts markdown to HTML with the default renderer
+func Render(source []byte) Result {
+	return Default.Render(source)
+}
@@ -0,0 +1,93 @@
+package markdown
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// calloutLabels maps GitHub-style callout markers ("> [!NOTE]") to their titles
+var calloutLabels = map[string]string{
+	"NOTE":      "メモ",
+	"TIP":       "ヒント",
+	"IMPORTANT": "重要",
+	"WARNING":   "注意",
+	"CAUTION":   "警告",
+}
+
+var calloutMarkerPattern = regexp.MustCompile(`^\[!([A-Za-z]+)\]$`)
+
+// transformCallouts turns blockquotes starting with "[!NOTE]" etc. into callouts:
+// <blockquote class="callout callout-note"> with a title paragraph.
+func transformCallouts(doc *ast.Document, source []byte) {
+	var quotes []*ast.Blockquote
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if q, ok := n.(*ast.Blockquote); ok && entering {
+			quotes = append(quotes, q)
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, quote := range quotes {
+		para, ok := quote.FirstChild().(*ast.Paragraph)
+		if !ok {
+			continue
+		}
+
+		// 最初の行（改行まで）のテキストノードを集める。"[" "!NOTE" "]" に分かれていることがある
+		var marker bytes.Buffer
+		var markerNodes []ast.Node
+		endsLine := false
+		for n := para.FirstChild(); n != nil && !endsLine; n = n.NextSibling() {
+			t, ok := n.(*ast.Text)
+			if !ok {
+				break
+			}
+			marker.Write(t.Segment.Value(source))
+			markerNodes = append(markerNodes, n)
+			endsLine = t.SoftLineBreak() || t.HardLineBreak()
+		}
+
+		m := calloutMarkerPattern.FindStringSubmatch(strings.TrimSpace(marker.String()))
+		if m == nil || (!endsLine && markerNodes[len(markerNodes)-1].NextSibling() != nil) {
+			continue
+		}
+		kind := strings.ToUpper(m[1])
+		label, ok := calloutLabels[kind]
+		if !ok {
+			continue
+		}
+
+		for _, n := range markerNodes {
+			para.RemoveChild(para, n)
+		}
+		if para.ChildCount() == 0 {
+			quote.RemoveChild(quote, para)
+		}
+
+		title := ast.NewParagraph()
+		title.SetAttributeString("class", []byte("callout-title"))
+		title.AppendChild(title, ast.NewString([]byte(label)))
+		if first := quote.FirstChild(); first != nil {
+			quote.InsertBefore(quote, first, title)
+		} else {
+			quote.AppendChild(quote, title)
+		}
+		quote.SetAttributeString("class", []byte("callout callout-"+strings.ToLower(kind)))
+	}
+}
+
+// RewriteLinks returns a transformer that replaces each link destination with
+// rewrite(dest). Images and autolinks are left alone.
+func RewriteLinks(rewrite func(dest string) string) Transformer {
+	return TransformerFunc(func(doc *ast.Document, source []byte) {
+		ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+			if link, ok := n.(*ast.Link); ok && entering {
+				link.Destination = []byte(rewrite(string(link.Destination)))
+			}
+			return ast.WalkContinue, nil
+		})
+	})
+}
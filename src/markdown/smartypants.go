@@ -0,0 +1,382 @@
+// SmartyPants below is ported from blackfriday (smartypants.go),
+// Copyright © 2011 Russ Ross <russ@russross.com>, Simplified BSD License.
+
+package markdown
+
+import (
+	"bufio"
+	"bytes"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+// applySmartypants replaces quotes, dashes, "..." and fractions in the text of
+// doc the way blackfriday did with CommonHTMLFlags (Smartypants, fractions and
+// LaTeX dashes), so articles keep their typography after the switch to goldmark.
+//
+// Like blackfriday it works on escaped HTML, one run of text at a time (a run
+// ends at emphasis, code, links and line breaks), with the quote state carried
+// through the document. Runs are replaced by pre-rendered strings, so it must be
+// the last step before rendering.
+func applySmartypants(doc *ast.Document, source []byte) {
+	var runs [][]*ast.Text
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n.Kind() {
+		case ast.KindCodeSpan, ast.KindImage, ast.KindAutoLink:
+			return ast.WalkSkipChildren, nil
+		}
+		t, ok := n.(*ast.Text)
+		if !ok || t.IsRaw() {
+			return ast.WalkContinue, nil
+		}
+		// 直前のテキストと同じ連続部分か（強調・改行などで区切る）
+		if prev, ok := t.PreviousSibling().(*ast.Text); ok && !prev.IsRaw() && !prev.HardLineBreak() && len(runs) > 0 {
+			if last := runs[len(runs)-1]; last[len(last)-1] == prev {
+				runs[len(runs)-1] = append(last, t)
+				return ast.WalkContinue, nil
+			}
+		}
+		runs = append(runs, []*ast.Text{t})
+		return ast.WalkContinue, nil
+	})
+
+	sp := &smartypants{}
+	for _, run := range runs {
+		// goldmark と同じ方法でエスケープした本文に置き換えを適用
+		var escaped bytes.Buffer
+		w := bufio.NewWriter(&escaped)
+		for _, t := range run {
+			html.DefaultWriter.Write(w, t.Segment.Value(source))
+			if t.SoftLineBreak() && !t.HardLineBreak() {
+				w.WriteByte('\n')
+			}
+		}
+		w.Flush()
+
+		var out bytes.Buffer
+		sp.process(&out, escaped.Bytes())
+		last := run[len(run)-1]
+		if last.HardLineBreak() {
+			out.WriteString("<br />\n")
+		}
+
+		s := ast.NewString(out.Bytes())
+		s.SetCode(true) // エスケープ済み
+		parent := run[0].Parent()
+		parent.InsertBefore(parent, run[0], s)
+		for _, t := range run {
+			parent.RemoveChild(parent, t)
+		}
+	}
+}
+
+// smartypants holds the quote state of one document
+type smartypants struct {
+	inSingleQuote bool
+	inDoubleQuote bool
+}
+
+func (sp *smartypants) process(out *bytes.Buffer, text []byte) {
+	mark := 0
+	for i := 0; i < len(text); i++ {
+		action := sp.callback(text[i])
+		if action == nil {
+			continue
+		}
+		out.Write(text[mark:i])
+		previousChar := byte(0)
+		if i > 0 {
+			previousChar = text[i-1]
+		}
+		i += action(out, previousChar, text[i:])
+		mark = i + 1
+	}
+	if mark < len(text) {
+		out.Write(text[mark:])
+	}
+}
+
+type smartCallback func(out *bytes.Buffer, previousChar byte, text []byte) int
+
+func (sp *smartypants) callback(c byte) smartCallback {
+	switch c {
+	case '"':
+		return sp.smartDoubleQuote
+	case '&':
+		return sp.smartAmp
+	case '\'':
+		return sp.smartSingleQuote
+	case '(':
+		return smartParens
+	case '-':
+		return smartDashLatex
+	case '.':
+		return smartPeriod
+	case '<':
+		return smartLeftAngle
+	case '`':
+		return sp.smartBacktick
+	}
+	if c >= '1' && c <= '9' {
+		return smartNumberGeneric
+	}
+	return nil
+}
+
+func isspace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
+
+func ispunct(c byte) bool {
+	for _, r := range []byte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~") {
+		if c == r {
+			return true
+		}
+	}
+	return false
+}
+
+func wordBoundary(c byte) bool {
+	return c == 0 || isspace(c) || ispunct(c)
+}
+
+func tolower(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c - 'A' + 'a'
+	}
+	return c
+}
+
+func isdigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// 前後の文字（0 は連続部分の端）から開き・閉じを決める。日本語はバイト単位で「その他の文字」扱い
+func smartQuoteHelper(out *bytes.Buffer, previousChar, nextChar byte, quote byte, isOpen *bool) {
+	switch {
+	case previousChar == 0 && nextChar == 0:
+		*isOpen = !*isOpen
+	case isspace(previousChar) && nextChar == 0:
+		*isOpen = true
+	case ispunct(previousChar) && nextChar == 0:
+		*isOpen = false
+	case nextChar == 0:
+		*isOpen = false
+	case previousChar == 0 && isspace(nextChar):
+		*isOpen = false
+	case isspace(previousChar) && isspace(nextChar):
+		*isOpen = !*isOpen
+	case ispunct(previousChar) && isspace(nextChar):
+		*isOpen = false
+	case isspace(nextChar):
+		*isOpen = false
+	case previousChar == 0 && ispunct(nextChar):
+		*isOpen = false
+	case isspace(previousChar) && ispunct(nextChar):
+		*isOpen = true
+	case ispunct(previousChar) && ispunct(nextChar):
+		*isOpen = !*isOpen
+	case ispunct(nextChar):
+		*isOpen = false
+	case previousChar == 0, isspace(previousChar), ispunct(previousChar):
+		*isOpen = true
+	default:
+		*isOpen = false
+	}
+
+	out.WriteByte('&')
+	if *isOpen {
+		out.WriteByte('l')
+	} else {
+		out.WriteByte('r')
+	}
+	out.WriteByte(quote)
+	out.WriteString("quo;")
+}
+
+func (sp *smartypants) smartSingleQuote(out *bytes.Buffer, previousChar byte, text []byte) int {
+	if len(text) >= 2 {
+		t1 := tolower(text[1])
+
+		if t1 == '\'' {
+			nextChar := byte(0)
+			if len(text) >= 3 {
+				nextChar = text[2]
+			}
+			smartQuoteHelper(out, previousChar, nextChar, 'd', &sp.inDoubleQuote)
+			return 1
+		}
+
+		// 短縮形（'s 't 'm 'd 're 'll 've）
+		if (t1 == 's' || t1 == 't' || t1 == 'm' || t1 == 'd') && (len(text) < 3 || wordBoundary(text[2])) {
+			out.WriteString("&rsquo;")
+			return 0
+		}
+		if len(text) >= 3 {
+			t2 := tolower(text[2])
+			if ((t1 == 'r' && t2 == 'e') || (t1 == 'l' && t2 == 'l') || (t1 == 'v' && t2 == 'e')) &&
+				(len(text) < 4 || wordBoundary(text[3])) {
+				out.WriteString("&rsquo;")
+				return 0
+			}
+		}
+	}
+
+	nextChar := byte(0)
+	if len(text) > 1 {
+		nextChar = text[1]
+	}
+	smartQuoteHelper(out, previousChar, nextChar, 's', &sp.inSingleQuote)
+	return 0
+}
+
+func (sp *smartypants) smartDoubleQuote(out *bytes.Buffer, previousChar byte, text []byte) int {
+	nextChar := byte(0)
+	if len(text) > 1 {
+		nextChar = text[1]
+	}
+	smartQuoteHelper(out, previousChar, nextChar, 'd', &sp.inDoubleQuote)
+	return 0
+}
+
+func smartParens(out *bytes.Buffer, previousChar byte, text []byte) int {
+	if len(text) >= 3 {
+		t1 := tolower(text[1])
+		t2 := tolower(text[2])
+
+		if t1 == 'c' && t2 == ')' {
+			out.WriteString("&copy;")
+			return 2
+		}
+		if t1 == 'r' && t2 == ')' {
+			out.WriteString("&reg;")
+			return 2
+		}
+		if len(text) >= 4 && t1 == 't' && t2 == 'm' && text[3] == ')' {
+			out.WriteString("&trade;")
+			return 3
+		}
+	}
+
+	out.WriteByte(text[0])
+	return 0
+}
+
+// "---" は全角ダッシュ、"--" は二分ダッシュ
+func smartDashLatex(out *bytes.Buffer, previousChar byte, text []byte) int {
+	if len(text) >= 3 && text[1] == '-' && text[2] == '-' {
+		out.WriteString("&mdash;")
+		return 2
+	}
+	if len(text) >= 2 && text[1] == '-' {
+		out.WriteString("&ndash;")
+		return 1
+	}
+
+	out.WriteByte(text[0])
+	return 0
+}
+
+// エスケープ済みの " は &quot; になっている
+func (sp *smartypants) smartAmp(out *bytes.Buffer, previousChar byte, text []byte) int {
+	if bytes.HasPrefix(text, []byte("&quot;")) {
+		nextChar := byte(0)
+		if len(text) >= 7 {
+			nextChar = text[6]
+		}
+		smartQuoteHelper(out, previousChar, nextChar, 'd', &sp.inDoubleQuote)
+		return 5
+	}
+	if bytes.HasPrefix(text, []byte("&#0;")) {
+		return 3
+	}
+
+	out.WriteByte('&')
+	return 0
+}
+
+func smartPeriod(out *bytes.Buffer, previousChar byte, text []byte) int {
+	if len(text) >= 3 && text[1] == '.' && text[2] == '.' {
+		out.WriteString("&hellip;")
+		return 2
+	}
+	if len(text) >= 5 && text[1] == ' ' && text[2] == '.' && text[3] == ' ' && text[4] == '.' {
+		out.WriteString("&hellip;")
+		return 4
+	}
+
+	out.WriteByte(text[0])
+	return 0
+}
+
+func (sp *smartypants) smartBacktick(out *bytes.Buffer, previousChar byte, text []byte) int {
+	if len(text) >= 2 && text[1] == '`' {
+		nextChar := byte(0)
+		if len(text) >= 3 {
+			nextChar = text[2]
+		}
+		smartQuoteHelper(out, previousChar, nextChar, 'd', &sp.inDoubleQuote)
+		return 1
+	}
+
+	out.WriteByte(text[0])
+	return 0
+}
+
+// 3/4 などの分数（日付のような 1/23/2005 は除く）
+func smartNumberGeneric(out *bytes.Buffer, previousChar byte, text []byte) int {
+	if wordBoundary(previousChar) && previousChar != '/' && len(text) >= 3 {
+		numEnd := 0
+		for len(text) > numEnd && isdigit(text[numEnd]) {
+			numEnd++
+		}
+		if numEnd == 0 {
+			out.WriteByte(text[0])
+			return 0
+		}
+		denStart := numEnd + 1
+		if len(text) > numEnd+3 && text[numEnd] == 0xe2 && text[numEnd+1] == 0x81 && text[numEnd+2] == 0x84 {
+			denStart = numEnd + 3 // 分数スラッシュ（⁄）
+		} else if len(text) < numEnd+2 || text[numEnd] != '/' {
+			out.WriteByte(text[0])
+			return 0
+		}
+		denEnd := denStart
+		for len(text) > denEnd && isdigit(text[denEnd]) {
+			denEnd++
+		}
+		if denEnd == denStart {
+			out.WriteByte(text[0])
+			return 0
+		}
+		if len(text) == denEnd || wordBoundary(text[denEnd]) && text[denEnd] != '/' {
+			out.WriteString("<sup>")
+			out.Write(text[:numEnd])
+			out.WriteString("</sup>&frasl;<sub>")
+			out.Write(text[denStart:denEnd])
+			out.WriteString("</sub>")
+			return denEnd - 1
+		}
+	}
+
+	out.WriteByte(text[0])
+	return 0
+}
+
+// タグはそのまま
+func smartLeftAngle(out *bytes.Buffer, previousChar byte, text []byte) int {
+	i := 0
+	for i < len(text) && text[i] != '>' {
+		i++
+	}
+	if i == len(text) {
+		out.Write(text)
+		return i - 1
+	}
+	out.Write(text[:i+1])
+	return i
+}
@@ -2,27 +2,58 @@ package models
 
 import (
 	"html/template"
+	"regexp"
 	"strings"
 	"time"
-	"github.com/russross/blackfriday/v2"
 )
 
 // BlogPost represents a blog article
 type BlogPost struct {
-	ID          uint      `json:"id"`
-	Slug        string    `json:"slug"`
-	Title       string    `json:"title"`
-	Content     string    `json:"content"`
-	Description string    `json:"description"`
-	Icon        string    `json:"icon"`
-	MarkdownPath string   `json:"markdown_path"` // .mdファイルパス
-	CreatedDate time.Time `json:"created_date"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	Published     bool        `json:"published"`
-	PrevPost      *BlogPost   `json:"prev_post,omitempty"`      // 前の記事
-	NextPost      *BlogPost   `json:"next_post,omitempty"`      // 次の記事
-	RelatedPosts  []BlogPost  `json:"related_posts,omitempty"`  // 関連記事
+	ID           uint          `json:"id"`
+	Slug         string        `json:"slug"`
+	Title        string        `json:"title"`
+	Content      string        `json:"content"`
+	Description  string        `json:"description"`
+	Icon         string        `json:"icon"`
+	MarkdownPath string        `json:"markdown_path"` // .mdファイルパス
+	CreatedDate  time.Time     `json:"created_date"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+	Published    bool          `json:"published"`
+	Tags         []string      `json:"tags,omitempty"`
+	Category     string        `json:"category,omitempty"`
+	Series       string        `json:"series,omitempty"`       // シリーズ名（URL用）
+	SeriesTitle  string        `json:"series_title,omitempty"` // シリーズの表示名
+	SeriesPart   int           `json:"series_part,omitempty"`  // シリーズ内の番号（1始まり）
+	Aliases      []string      `json:"aliases,omitempty"`
+	Canonical    string        `json:"canonical,omitempty"`
+	OGImage      string        `json:"og_image,omitempty"`
+	PrevPost     *BlogPost     `json:"prev_post,omitempty"`     // 前の記事
+	NextPost     *BlogPost     `json:"next_post,omitempty"`     // 次の記事
+	RelatedPosts []BlogPost    `json:"related_posts,omitempty"` // 関連記事
+	SeriesNav    *SeriesNav    `json:"series_nav,omitempty"`    // シリーズ目次
+	TOC          []TOCEntry    `json:"toc,omitempty"`           // 記事内の見出し目次
+	Stats        *ContentStats `json:"stats,omitempty"`         // 文字数・読了時間など
+
+	render *renderCache // レンダリング結果のキャッシュ（読み込み時に作成）
+}
+
+// SeriesNav is the table of contents of the series a post belongs to
+type SeriesNav struct {
+	Name  string     `json:"name"`
+	Title string     `json:"title"`
+	Part  int        `json:"part"`
+	Posts []BlogPost `json:"posts"`
+	Prev  *BlogPost  `json:"prev,omitempty"` // シリーズ内の前の記事
+	Next  *BlogPost  `json:"next,omitempty"` // シリーズ内の次の記事
+}
+
+// SeriesDisplayTitle returns the series title, falling back to its name
+func (b *BlogPost) SeriesDisplayTitle() string {
+	if b.SeriesTitle != "" {
+		return b.SeriesTitle
+	}
+	return b.Series
 }
 
 // TableNameメソッドはファイルベースでは不要
@@ -42,6 +73,58 @@ func (b *BlogPost) ToMarkdown() string {
 	return result
 }
 
+// Markdown記法の除去用パターン
+var (
+	markdownImagePattern    = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)`)
+	markdownLinkPattern     = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	markdownHTMLTagPattern  = regexp.MustCompile(`<[^>]+>`)
+	markdownLinePrefix      = regexp.MustCompile(`^(#{1,6}\s+|>\s*|[-*+]\s+(\[[ xX]\]\s+)?|\d+\.\s+)`)
+	markdownEmphasisPattern = regexp.MustCompile("(\\*\\*|__|\\*|`|~~)")
+)
+
+// PlainText returns the content with markdown syntax stripped, as a single line of text
+func (b *BlogPost) PlainText() string {
+	var parts []string
+	for _, line := range strings.Split(b.Content, "\n") {
+		line = strings.TrimSpace(line)
+		// コードフェンス・水平線・表の区切り行は除外
+		if strings.HasPrefix(line, "```") || strings.HasPrefix(line, "---") || strings.HasPrefix(line, "|-") || strings.HasPrefix(line, "| -") {
+			continue
+		}
+		line = markdownImagePattern.ReplaceAllString(line, "")
+		line = markdownLinkPattern.ReplaceAllString(line, "$1")
+		line = markdownHTMLTagPattern.ReplaceAllString(line, "")
+		line = markdownLinePrefix.ReplaceAllString(line, "")
+		line = markdownEmphasisPattern.ReplaceAllString(line, "")
+		line = strings.TrimSpace(strings.ReplaceAll(line, "|", " "))
+		if line != "" {
+			parts = append(parts, strings.Join(strings.Fields(line), " "))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// IsVisible reports whether the post is published and its publish time has passed
+func (b *BlogPost) IsVisible(now time.Time) bool {
+	return b.Published && !b.CreatedDate.After(now)
+}
+
+// IsScheduled reports whether the post is published but dated in the future
+func (b *BlogPost) IsScheduled(now time.Time) bool {
+	return b.Published && b.CreatedDate.After(now)
+}
+
+// SeriesBefore reports whether the post comes before other within a series:
+// numbered parts in order, then unnumbered posts oldest first
+func (b *BlogPost) SeriesBefore(other *BlogPost) bool {
+	if (b.SeriesPart == 0) != (other.SeriesPart == 0) {
+		return other.SeriesPart == 0
+	}
+	if b.SeriesPart != other.SeriesPart {
+		return b.SeriesPart < other.SeriesPart
+	}
+	return b.CreatedDate.Before(other.CreatedDate)
+}
 
 // IsIconURL checks if the icon field contains a URL or path
 func (b *BlogPost) IsIconURL() bool {
@@ -49,19 +132,11 @@ func (b *BlogPost) IsIconURL() bool {
 		return false
 	}
 	return strings.HasPrefix(b.Icon, "http") ||
-	       strings.HasPrefix(b.Icon, "./") ||
-	       strings.HasPrefix(b.Icon, "/")
+		strings.HasPrefix(b.Icon, "./") ||
+		strings.HasPrefix(b.Icon, "/")
 }
 
 // RenderContent renders the markdown content as HTML
 func (b *BlogPost) RenderContent() template.HTML {
-	// MarkdownをHTMLに変換
-	renderer := blackfriday.NewHTMLRenderer(blackfriday.HTMLRendererParameters{
-		Flags: blackfriday.CommonHTMLFlags,
-	})
-
-	extensions := blackfriday.CommonExtensions | blackfriday.AutoHeadingIDs
-	html := blackfriday.Run([]byte(b.Content), blackfriday.WithRenderer(renderer), blackfriday.WithExtensions(extensions))
-
-	return template.HTML(html)
-}
\ No newline at end of file
+	return b.Rendered().HTML
+}
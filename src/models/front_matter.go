@@ -0,0 +1,110 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FrontMatter represents the optional YAML block at the top of an article
+type FrontMatter struct {
+	Title       string   `yaml:"title"`
+	Description string   `yaml:"description"`
+	Date        string   `yaml:"date"`
+	Updated     string   `yaml:"updated"`
+	Icon        string   `yaml:"icon"`
+	Draft       bool     `yaml:"draft"`
+	Tags        []string `yaml:"tags"`
+	Category    string   `yaml:"category"`
+	Series      string   `yaml:"series"`
+	SeriesTitle string   `yaml:"series_title"`
+	SeriesPart  int      `yaml:"series_part"`
+	Slug        string   `yaml:"slug"`
+	Aliases     []string `yaml:"aliases"`
+	Canonical   string   `yaml:"canonical"`
+	OGImage     string   `yaml:"og_image"`
+}
+
+// PostLocation is the time zone of dates written in articles (front matter and
+// file names). It is fixed so post dates do not depend on the server's TZ.
+var PostLocation = time.FixedZone("JST", 9*60*60)
+
+// frontMatterDateLayouts are the accepted formats for date/updated
+var frontMatterDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// ParseFrontMatter splits a "---" delimited YAML block from the markdown body.
+// Content without front matter is returned unchanged with a zero FrontMatter.
+func ParseFrontMatter(content string) (FrontMatter, string, error) {
+	var fm FrontMatter
+
+	// BOMとCRLFを考慮
+	text := strings.TrimPrefix(content, "\uFEFF")
+	if !strings.HasPrefix(text, "---\n") && !strings.HasPrefix(text, "---\r\n") {
+		return fm, content, nil
+	}
+
+	rest := text[strings.Index(text, "\n")+1:]
+	end, bodyStart := -1, -1
+	offset := 0
+	for _, line := range strings.SplitAfter(rest, "\n") {
+		if strings.TrimRight(line, "\r\n") == "---" {
+			end, bodyStart = offset, offset+len(line)
+			break
+		}
+		offset += len(line)
+	}
+	if end < 0 {
+		// 閉じ区切りがない場合は本文の水平線とみなす
+		return fm, content, nil
+	}
+
+	if err := yaml.Unmarshal([]byte(rest[:end]), &fm); err != nil {
+		return fm, content, fmt.Errorf("front matter: %w", err)
+	}
+
+	return fm, strings.TrimLeft(rest[bodyStart:], "\r\n"), nil
+}
+
+// ParsedDate returns the front matter date, or the zero time if unset or invalid
+func (fm FrontMatter) ParsedDate() time.Time {
+	return parseFrontMatterDate(fm.Date)
+}
+
+// ParsedUpdated returns the front matter updated date, or the zero time if unset or invalid
+func (fm FrontMatter) ParsedUpdated() time.Time {
+	return parseFrontMatterDate(fm.Updated)
+}
+
+func parseFrontMatterDate(value string) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}
+	}
+	for _, layout := range frontMatterDateLayouts {
+		if t, err := time.ParseInLocation(layout, value, PostLocation); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// DateFromFileName returns the date a file name like "2024-06-01-foo.md" starts
+// with, or the zero time
+func DateFromFileName(name string) time.Time {
+	if len(name) < 10 || name[4] != '-' || name[7] != '-' {
+		return time.Time{}
+	}
+	t, err := time.ParseInLocation("2006-01-02", name[:10], PostLocation)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
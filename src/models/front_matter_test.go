@@ -0,0 +1,106 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseFrontMatter(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		wantTitle string
+		wantBody  string
+		wantErr   bool
+	}{
+		{"none", "# Title\n\nbody\n", "", "# Title\n\nbody\n", false},
+		{"lf", "---\ntitle: LF\n---\n\nbody\n", "LF", "body\n", false},
+		{"crlf", "---\r\ntitle: CRLF\r\n---\r\n\r\nbody\r\n", "CRLF", "body\r\n", false},
+		{"bom", "\uFEFF---\ntitle: BOM\n---\nbody\n", "BOM", "body\n", false},
+		// 閉じ区切りがなければ水平線として本文のまま
+		{"unclosed", "---\ntitle: no end\n\nbody\n", "", "---\ntitle: no end\n\nbody\n", false},
+		{"invalid yaml", "---\ntitle: [broken\n---\nbody\n", "", "---\ntitle: [broken\n---\nbody\n", true},
+		{"empty", "---\n---\nbody\n", "", "body\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fm, body, err := ParseFrontMatter(tt.content)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if fm.Title != tt.wantTitle {
+				t.Errorf("title = %q, want %q", fm.Title, tt.wantTitle)
+			}
+			if body != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestParseFrontMatterFields(t *testing.T) {
+	fm, _, err := ParseFrontMatter("---\ntitle: T\ndraft: true\ntags: [Go, Web]\nseries: go-intro\nseries_part: 2\nslug: custom\naliases: [old]\n---\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fm.Draft || len(fm.Tags) != 2 || fm.Series != "go-intro" || fm.SeriesPart != 2 || fm.Slug != "custom" || len(fm.Aliases) != 1 {
+		t.Errorf("fields not parsed: %+v", fm)
+	}
+}
+
+func TestParseFrontMatterDate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2024-06-01T10:30:00+09:00", time.Date(2024, 6, 1, 10, 30, 0, 0, PostLocation)},
+		{"2024-06-01T10:30:00Z", time.Date(2024, 6, 1, 19, 30, 0, 0, PostLocation)},
+		{"2024-06-01T10:30:00", time.Date(2024, 6, 1, 10, 30, 0, 0, PostLocation)},
+		{"2024-06-01 10:30:00", time.Date(2024, 6, 1, 10, 30, 0, 0, PostLocation)},
+		{"2024-06-01 10:30", time.Date(2024, 6, 1, 10, 30, 0, 0, PostLocation)},
+		{"2024-06-01", time.Date(2024, 6, 1, 0, 0, 0, 0, PostLocation)},
+		{" 2024-06-01 ", time.Date(2024, 6, 1, 0, 0, 0, 0, PostLocation)},
+		{"", time.Time{}},
+		{"June 1, 2024", time.Time{}},
+		{"2024-13-01", time.Time{}},
+	}
+	for _, tt := range tests {
+		got := parseFrontMatterDate(tt.value)
+		if !got.Equal(tt.want) || got.IsZero() != tt.want.IsZero() {
+			t.Errorf("parseFrontMatterDate(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+// 日付はサーバーのタイムゾーンに関係なく記事のタイムゾーンで解釈する
+func TestParseFrontMatterDateIgnoresLocal(t *testing.T) {
+	saved := time.Local
+	defer func() { time.Local = saved }()
+	time.Local = time.UTC
+
+	got := parseFrontMatterDate("2024-06-01")
+	if want := time.Date(2024, 6, 1, 0, 0, 0, 0, PostLocation); !got.Equal(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if file := DateFromFileName("2024-06-01-foo"); !file.Equal(got) {
+		t.Errorf("file name date %v differs from front matter date %v", file, got)
+	}
+}
+
+func TestDateFromFileName(t *testing.T) {
+	tests := []struct {
+		name string
+		want time.Time
+	}{
+		{"2024-06-01-foo", time.Date(2024, 6, 1, 0, 0, 0, 0, PostLocation)},
+		{"2024-06-01", time.Date(2024, 6, 1, 0, 0, 0, 0, PostLocation)},
+		{"foo", time.Time{}},
+		{"2024-6-1-foo", time.Time{}},
+		{"2024-99-01-foo", time.Time{}},
+	}
+	for _, tt := range tests {
+		if got := DateFromFileName(tt.name); !got.Equal(tt.want) || got.IsZero() != tt.want.IsZero() {
+			t.Errorf("DateFromFileName(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -17,4 +17,4 @@ type Page struct {
 	UpdatedAt       time.Time `json:"updated_at"`
 }
 
-// TableNameメソッドはファイルベースでは不要
\ No newline at end of file
+// TableNameメソッドはファイルベースでは不要
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"html/template"
+	"math"
+	"sync"
+	"unicode"
+
+	"infohiroki-go/src/markdown"
+)
+
+// Reading speed used for ReadingTime
+const (
+	readingCharsPerMinute = 500 // 日本語（文字数）
+	readingWordsPerMinute = 200 // 英語など（単語数）
+)
+
+// Rendered is everything derived from a post's markdown by rendering it
+type Rendered struct {
+	HTML  template.HTML
+	TOC   []TOCEntry
+	Stats ContentStats
+}
+
+// ContentStats is metadata derived from the post body
+type ContentStats struct {
+	WordCount   int    `json:"word_count"`            // 英単語数＋日本語の文字数
+	ReadingTime int    `json:"reading_time"`          // 分（切り上げ、最低1分）
+	FirstImage  string `json:"first_image,omitempty"` // 本文中の最初の画像
+}
+
+// renderCache renders a post at most once. It is created when the source file
+// is loaded, so a changed file gets a fresh cache; copies of the post share it.
+type renderCache struct {
+	once     sync.Once
+	rendered *Rendered
+}
+
+// EnableRenderCache makes Rendered compute its result once and reuse it.
+// Call it after Content is final (e.g. when the file is loaded).
+func (b *BlogPost) EnableRenderCache() {
+	b.render = &renderCache{}
+}
+
+// Rendered returns the rendered HTML, TOC and stats, from the cache when enabled
+func (b *BlogPost) Rendered() *Rendered {
+	if b.render == nil {
+		return b.renderUncached()
+	}
+	b.render.once.Do(func() {
+		b.render.rendered = b.renderUncached()
+	})
+	return b.render.rendered
+}
+
+func (b *BlogPost) renderUncached() *Rendered {
+	result := markdown.Render([]byte(b.Content))
+
+	stats := countText(b.PlainText())
+	if len(result.Images) > 0 {
+		stats.FirstImage = result.Images[0]
+	}
+
+	return &Rendered{
+		HTML:  template.HTML(result.HTML),
+		TOC:   buildTOC(result.Headings),
+		Stats: stats,
+	}
+}
+
+// 日本語は1文字、英数字は連続部分を1語として数える
+func countText(text string) ContentStats {
+	var chars, words int
+	inWord := false
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana):
+			chars++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !inWord {
+				words++
+			}
+			inWord = true
+		default:
+			inWord = false
+		}
+	}
+
+	minutes := float64(chars)/readingCharsPerMinute + float64(words)/readingWordsPerMinute
+	return ContentStats{
+		WordCount:   chars + words,
+		ReadingTime: int(math.Max(1, math.Ceil(minutes))),
+	}
+}
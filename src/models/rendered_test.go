@@ -0,0 +1,31 @@
+package models
+
+import (
+	"os"
+	"testing"
+)
+
+func BenchmarkRenderContent(b *testing.B) {
+	content, err := os.ReadFile("../../articles/2025-09-30-zellij-complete-guide.md")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("uncached", func(b *testing.B) {
+		post := &BlogPost{Content: string(content)}
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			post.RenderContent()
+		}
+	})
+	b.Run("cached", func(b *testing.B) {
+		post := &BlogPost{Content: string(content)}
+		post.EnableRenderCache()
+		post.RenderContent() // 初回のレンダリングは計測しない
+		b.ResetTimer()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			post.RenderContent()
+		}
+	})
+}
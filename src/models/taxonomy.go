@@ -0,0 +1,137 @@
+package models
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TaxonomyRule assigns tags and a category to posts whose title or slug contains one of Keywords
+type TaxonomyRule struct {
+	Keywords []string `yaml:"keywords"`
+	Tags     []string `yaml:"tags"`
+	Category string   `yaml:"category"`
+}
+
+// SeriesRule groups posts into a series; Posts lists slugs in reading order
+type SeriesRule struct {
+	Name  string   `yaml:"name"`
+	Title string   `yaml:"title"`
+	Posts []string `yaml:"posts"`
+}
+
+// TaxonomyRules is the optional rules file used to classify legacy posts without front matter
+type TaxonomyRules struct {
+	Rules  []TaxonomyRule `yaml:"rules"`
+	Series []SeriesRule   `yaml:"series"`
+}
+
+// LoadTaxonomyRules reads a rules file; a missing file yields empty rules
+func LoadTaxonomyRules(path string) (TaxonomyRules, error) {
+	var rules TaxonomyRules
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return rules, nil
+	}
+	if err != nil {
+		return rules, err
+	}
+
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return rules, fmt.Errorf("%s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// Apply fills in tags and category from matching rules.
+// Values already set (e.g. from front matter) are kept; rule tags are only added when the post has none.
+func (r TaxonomyRules) Apply(post *BlogPost) {
+	hasTags := len(post.Tags) > 0
+	target := strings.ToLower(post.Title + " " + post.Slug)
+
+	var tags []string
+	for _, rule := range r.Rules {
+		if !rule.matches(target) {
+			continue
+		}
+		if !hasTags {
+			tags = append(tags, rule.Tags...)
+		}
+		if post.Category == "" && rule.Category != "" {
+			post.Category = NormalizeTag(rule.Category)
+		}
+	}
+
+	if !hasTags && len(tags) > 0 {
+		post.Tags = NormalizeTags(tags)
+	}
+
+	r.applySeries(post)
+}
+
+// シリーズ未指定の記事にルールのシリーズ・番号を設定
+func (r TaxonomyRules) applySeries(post *BlogPost) {
+	for _, series := range r.Series {
+		name := NormalizeTag(series.Name)
+		if post.Series == name {
+			if post.SeriesTitle == "" {
+				post.SeriesTitle = series.Title
+			}
+			return
+		}
+		if post.Series != "" {
+			continue
+		}
+		for i, slug := range series.Posts {
+			if slug == post.Slug {
+				post.Series = name
+				post.SeriesTitle = series.Title
+				post.SeriesPart = i + 1
+				return
+			}
+		}
+	}
+}
+
+func (rule TaxonomyRule) matches(target string) bool {
+	for _, keyword := range rule.Keywords {
+		if keyword != "" && strings.Contains(target, strings.ToLower(keyword)) {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeTag lowercases a tag and joins inner whitespace with hyphens
+func NormalizeTag(tag string) string {
+	return strings.Join(strings.Fields(strings.ToLower(tag)), "-")
+}
+
+// NormalizeTags normalizes tags and drops empty and duplicate entries, keeping order
+func NormalizeTags(tags []string) []string {
+	seen := map[string]bool{}
+	var result []string
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	return result
+}
+
+// HasTag reports whether the post carries tag (compared after normalization)
+func (b *BlogPost) HasTag(tag string) bool {
+	tag = NormalizeTag(tag)
+	for _, t := range b.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	if got := NormalizeTag("  Machine   Learning "); got != "machine-learning" {
+		t.Errorf("NormalizeTag = %q", got)
+	}
+	got := NormalizeTags([]string{"Go", "go", " ", "生成 AI", "Web"})
+	if want := []string{"go", "生成-ai", "web"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("NormalizeTags = %q, want %q", got, want)
+	}
+}
+
+func testRules() TaxonomyRules {
+	return TaxonomyRules{
+		Rules: []TaxonomyRule{
+			{Keywords: []string{"Docker", "kubernetes"}, Tags: []string{"Docker", "Infra"}, Category: "Dev Ops"},
+			{Keywords: []string{"compose"}, Tags: []string{"docker"}, Category: "other"},
+			{Keywords: []string{""}, Tags: []string{"everything"}},
+		},
+		Series: []SeriesRule{
+			{Name: "Docker Intro", Title: "Docker入門", Posts: []string{"docker-1", "docker-compose-2"}},
+		},
+	}
+}
+
+func TestTaxonomyRulesApply(t *testing.T) {
+	tests := []struct {
+		name string
+		post BlogPost
+		want BlogPost
+	}{
+		{
+			name: "keyword in title",
+			post: BlogPost{Slug: "a", Title: "Dockerの基本"},
+			want: BlogPost{Slug: "a", Title: "Dockerの基本", Tags: []string{"docker", "infra"}, Category: "dev-ops"},
+		},
+		{
+			// 複数のルールのタグはまとめ、カテゴリは最初のルール
+			name: "keyword in slug, several rules",
+			post: BlogPost{Slug: "docker-compose-tips", Title: "Tips"},
+			want: BlogPost{Slug: "docker-compose-tips", Title: "Tips", Tags: []string{"docker", "infra"}, Category: "dev-ops"},
+		},
+		{
+			// フロントマターの指定は上書きしない
+			name: "front matter kept",
+			post: BlogPost{Slug: "b", Title: "Kubernetes運用", Tags: []string{"k8s"}, Category: "ops"},
+			want: BlogPost{Slug: "b", Title: "Kubernetes運用", Tags: []string{"k8s"}, Category: "ops"},
+		},
+		{
+			name: "no match",
+			post: BlogPost{Slug: "c", Title: "Notion入門"},
+			want: BlogPost{Slug: "c", Title: "Notion入門"},
+		},
+		{
+			name: "series by slug",
+			post: BlogPost{Slug: "docker-compose-2", Title: "Compose"},
+			want: BlogPost{Slug: "docker-compose-2", Title: "Compose", Tags: []string{"docker", "infra"}, Category: "dev-ops",
+				Series: "docker-intro", SeriesTitle: "Docker入門", SeriesPart: 2},
+		},
+		{
+			// シリーズ名だけ指定した記事にはタイトルを補う
+			name: "series title from rule",
+			post: BlogPost{Slug: "d", Title: "d", Series: "docker-intro", SeriesPart: 5},
+			want: BlogPost{Slug: "d", Title: "d", Series: "docker-intro", SeriesTitle: "Docker入門", SeriesPart: 5},
+		},
+		{
+			name: "other series kept",
+			post: BlogPost{Slug: "docker-1", Title: "d", Series: "mine"},
+			want: BlogPost{Slug: "docker-1", Title: "d", Tags: []string{"docker", "infra"}, Category: "dev-ops", Series: "mine"},
+		},
+	}
+	rules := testRules()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			post := tt.post
+			rules.Apply(&post)
+			if !reflect.DeepEqual(post, tt.want) {
+				t.Errorf("got %+v\nwant %+v", post, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadTaxonomyRules(t *testing.T) {
+	dir := t.TempDir()
+
+	rules, err := LoadTaxonomyRules(filepath.Join(dir, "missing.yml"))
+	if err != nil || len(rules.Rules) != 0 {
+		t.Errorf("missing file: %+v, %v", rules, err)
+	}
+
+	path := filepath.Join(dir, "taxonomy.yml")
+	data := "rules:\n  - keywords: [docker]\n    tags: [docker]\n    category: dev\nseries:\n  - name: intro\n    posts: [a, b]\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rules, err = LoadTaxonomyRules(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules.Rules) != 1 || rules.Rules[0].Category != "dev" || len(rules.Series) != 1 || len(rules.Series[0].Posts) != 2 {
+		t.Errorf("rules = %+v", rules)
+	}
+
+	if err := os.WriteFile(path, []byte("rules: [broken\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadTaxonomyRules(path); err == nil {
+		t.Error("invalid YAML: want an error")
+	}
+}
@@ -0,0 +1,55 @@
+package models
+
+import "infohiroki-go/src/markdown"
+
+// TOC heading levels: the article title is h1, so the table of contents covers h2–h4
+const (
+	tocMinLevel = 2
+	tocMaxLevel = 4
+)
+
+// TOCEntry is one heading in the table of contents
+type TOCEntry struct {
+	Level    int        `json:"level"`
+	ID       string     `json:"id"`
+	Text     string     `json:"text"`
+	Children []TOCEntry `json:"children,omitempty"`
+}
+
+// buildTOC nests headings by level. A heading that skips levels (h2 → h4)
+// becomes a child of the nearest shallower heading.
+func buildTOC(headings []markdown.Heading) []TOCEntry {
+	var root []TOCEntry
+	// stack[i] は現在の入れ子の i 段目のスライスを指す
+	stack := []*[]TOCEntry{&root}
+	levels := []int{tocMinLevel - 1}
+
+	for _, h := range headings {
+		if h.Level < tocMinLevel || h.Level > tocMaxLevel || h.ID == "" {
+			continue
+		}
+		for len(levels) > 1 && levels[len(levels)-1] >= h.Level {
+			stack = stack[:len(stack)-1]
+			levels = levels[:len(levels)-1]
+		}
+		parent := stack[len(stack)-1]
+		*parent = append(*parent, TOCEntry{Level: h.Level, ID: h.ID, Text: h.Text})
+		entry := &(*parent)[len(*parent)-1]
+		stack = append(stack, &entry.Children)
+		levels = append(levels, h.Level)
+	}
+	return root
+}
+
+// TOCCount returns the number of headings in the table of contents
+func (b *BlogPost) TOCCount() int {
+	return countTOC(b.TOC)
+}
+
+func countTOC(entries []TOCEntry) int {
+	n := len(entries)
+	for _, entry := range entries {
+		n += countTOC(entry.Children)
+	}
+	return n
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+
+	"infohiroki-go/src/markdown"
+)
+
+func TestBuildTOC(t *testing.T) {
+	headings := []markdown.Heading{
+		{Level: 1, ID: "title", Text: "タイトル"},
+		{Level: 2, ID: "a", Text: "A"},
+		{Level: 3, ID: "a-1", Text: "A-1"},
+		{Level: 5, ID: "deep", Text: "深すぎる見出し"},
+		{Level: 3, ID: "a-2", Text: "A-2"},
+		{Level: 2, ID: "b", Text: "B"},
+		// h2 → h4 と飛ばした見出しは直前の h2 の子
+		{Level: 4, ID: "b-1", Text: "B-1"},
+		{Level: 3, ID: "b-2", Text: "B-2"},
+		{Level: 2, ID: "", Text: "IDなし"},
+	}
+	want := []TOCEntry{
+		{Level: 2, ID: "a", Text: "A", Children: []TOCEntry{
+			{Level: 3, ID: "a-1", Text: "A-1"},
+			{Level: 3, ID: "a-2", Text: "A-2"},
+		}},
+		{Level: 2, ID: "b", Text: "B", Children: []TOCEntry{
+			{Level: 4, ID: "b-1", Text: "B-1"},
+			{Level: 3, ID: "b-2", Text: "B-2"},
+		}},
+	}
+	got := buildTOC(headings)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("buildTOC = %+v\nwant %+v", got, want)
+	}
+	if n := countTOC(got); n != 6 {
+		t.Errorf("countTOC = %d, want 6", n)
+	}
+}
+
+func TestBuildTOCStartsDeep(t *testing.T) {
+	// h3 から始まる記事も最上位に並べる
+	got := buildTOC([]markdown.Heading{{Level: 3, ID: "x", Text: "X"}, {Level: 2, ID: "y", Text: "Y"}})
+	want := []TOCEntry{{Level: 3, ID: "x", Text: "X"}, {Level: 2, ID: "y", Text: "Y"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("buildTOC = %+v, want %+v", got, want)
+	}
+	if got := buildTOC(nil); got != nil {
+		t.Errorf("buildTOC(nil) = %+v", got)
+	}
+}
+
+func TestRenderedTOC(t *testing.T) {
+	post := &BlogPost{Content: "# タイトル\n\n## はじめに\n\n### `go build` の使い方\n\n## はじめに\n\n## ！！\n"}
+	got := post.Rendered().TOC
+	want := []TOCEntry{
+		{Level: 2, ID: "はじめに", Text: "はじめに", Children: []TOCEntry{
+			{Level: 3, ID: "go-build-の使い方", Text: "go build の使い方"},
+		}},
+		// 重複は連番、記号だけの見出しは目次に含めない
+		{Level: 2, ID: "はじめに-1", Text: "はじめに"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TOC = %+v\nwant %+v", got, want)
+	}
+}
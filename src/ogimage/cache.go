@@ -0,0 +1,128 @@
+package ogimage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// テンプレートを変えたら上げる（生成済みの画像を作り直す）
+const templateVersion = 2
+
+// Cache stores rendered cards as PNG files, keyed by their content, so a card
+// is drawn again only when the title, icon or date changes. Each name (a post
+// slug) keeps only its latest card; older ones are deleted when it is written.
+type Cache struct {
+	dir string
+
+	mu       sync.Mutex
+	inflight map[string]*sync.WaitGroup // 生成中のファイル（同じカードの同時生成を防ぐ）
+}
+
+// NewCache returns a cache writing to dir
+func NewCache(dir string) *Cache {
+	return &Cache{dir: dir, inflight: map[string]*sync.WaitGroup{}}
+}
+
+// File returns the path of the PNG for the card of name, rendering it if it is not cached
+func (c *Cache) File(name string, card Card) (string, error) {
+	path := filepath.Join(c.dir, entryDir(name), key(card)+".png")
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+
+		c.mu.Lock()
+		if wg, busy := c.inflight[path]; busy {
+			c.mu.Unlock()
+			wg.Wait()
+			continue
+		}
+		wg := &sync.WaitGroup{}
+		wg.Add(1)
+		c.inflight[path] = wg
+		c.mu.Unlock()
+
+		err := write(card, path)
+		if err == nil {
+			prune(path)
+		}
+
+		c.mu.Lock()
+		delete(c.inflight, path)
+		c.mu.Unlock()
+		wg.Done()
+		if err != nil {
+			return "", err
+		}
+		return path, nil
+	}
+}
+
+// カードの内容から決まるファイル名
+func key(card Card) string {
+	iconStamp := ""
+	if card.IconFile != "" {
+		if stat, err := os.Stat(card.IconFile); err == nil {
+			iconStamp = stat.ModTime().String()
+		}
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("v%d\x00%s\x00%s\x00%s\x00%s\x00%s\x00%s\x00%s",
+		templateVersion, card.Title, card.Icon, card.IconFile, iconStamp, card.Label, card.Site, card.Date.Format("2006-01-02"))))
+	return hex.EncodeToString(sum[:12])
+}
+
+// 記事ごとのディレクトリ名（パスとして扱えない名前はハッシュにする）
+func entryDir(name string) string {
+	if name == "" || strings.HasPrefix(name, ".") || strings.ContainsAny(name, "/\\\x00") {
+		sum := sha256.Sum256([]byte(name))
+		return "_" + hex.EncodeToString(sum[:8])
+	}
+	return name
+}
+
+// 同じ記事の古い画像を削除（書き込み中の一時ファイルは残す）
+func prune(path string) {
+	dir := filepath.Dir(path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if entry.Name() == filepath.Base(path) || strings.HasPrefix(entry.Name(), ".tmp-") {
+			continue
+		}
+		os.Remove(filepath.Join(dir, entry.Name()))
+	}
+}
+
+func write(card Card, path string) error {
+	img, err := Render(card)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	// 書きかけのファイルを配信しないよう一時ファイルに書いてから置き換える
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*.png")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := encoder.Encode(tmp, img); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,80 @@
+package ogimage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testCard(title string) Card {
+	return Card{Title: title, Icon: "📝", Site: "infoHiroki", Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func pngFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*", "*.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestCacheReusesFile(t *testing.T) {
+	cache := NewCache(t.TempDir())
+	first, err := cache.File("post", testCard("タイトル"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := cache.File("post", testCard("タイトル"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Errorf("same card cached twice: %s, %s", first, second)
+	}
+}
+
+// タイトルを変えたら同じ記事の古い画像は消え、ほかの記事の画像は残る
+func TestCachePrunesStaleEntries(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewCache(dir)
+	old, err := cache.File("post", testCard("古いタイトル"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := cache.File("other", testCard("古いタイトル"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, err := cache.File("post", testCard("新しいタイトル"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Errorf("stale card %s not deleted", old)
+	}
+	for _, path := range []string{current, other} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("card %s: %v", path, err)
+		}
+	}
+	if files := pngFiles(t, dir); len(files) != 2 {
+		t.Errorf("cache holds %v, want 2 files", files)
+	}
+}
+
+func TestCacheEntryDirStaysInside(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewCache(dir)
+	for _, name := range []string{"", ".", "..", "../escape", "a/b", `a\b`} {
+		path, err := cache.File(name, testCard(name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rel, err := filepath.Rel(dir, path); err != nil || filepath.Dir(filepath.Dir(rel)) != "." {
+			t.Errorf("name %q cached at %s, outside its own directory in %s", name, path, dir)
+		}
+	}
+}
@@ -0,0 +1,372 @@
+// Package ogimage draws Open Graph card images (1200x630 PNG) for articles.
+//
+// Cards are rendered in pure Go with the bundled M+ 1p font, which covers
+// Japanese kana and kanji. Emoji icons are drawn from a small bundled set of
+// Noto Color Emoji images; other characters the font lacks are skipped.
+package ogimage
+
+import (
+	_ "embed"
+	"image"
+	"image/color"
+	_ "image/gif" // アイコン画像の読み込み用
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	_ "golang.org/x/image/webp"
+)
+
+// カードの大きさ（OGP・Twitterカード推奨サイズ）
+const (
+	Width  = 1200
+	Height = 630
+)
+
+//go:embed fonts/mplus-1p-regular.ttf
+var fontData []byte
+
+var (
+	fontOnce sync.Once
+	fontFile *opentype.Font
+	fontErr  error
+)
+
+func loadFont() (*opentype.Font, error) {
+	fontOnce.Do(func() {
+		fontFile, fontErr = opentype.Parse(fontData)
+	})
+	return fontFile, fontErr
+}
+
+// Card is the content of one image
+type Card struct {
+	Title    string
+	Icon     string // 絵文字など（同梱の画像もフォントの文字もなければラベルの頭文字）
+	IconFile string // アイコン画像のパス（Iconより優先）
+	Label    string // タイトル上の小見出し（カテゴリ等）
+	Site     string
+	Date     time.Time
+}
+
+// 配色（サイトのCSSに合わせる）
+var (
+	colorBackground = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	colorText       = color.RGBA{0x00, 0x00, 0x00, 0xff}
+	colorTextLight  = color.RGBA{0x66, 0x66, 0x66, 0xff}
+	colorBorder     = color.RGBA{0xee, 0xee, 0xee, 0xff}
+	colorAccent     = color.RGBA{0xe7, 0x3e, 0x8f, 0xff}
+	colorAccentBg   = color.RGBA{0xfd, 0xec, 0xf4, 0xff}
+)
+
+// レイアウト
+const (
+	padding      = 80
+	accentHeight = 16
+	iconSize     = 120
+	footerY      = 520 // 区切り線の位置
+	titleLines   = 3
+)
+
+// タイトルの文字サイズ（長いタイトルは小さくして3行に収める）
+var titleSizes = []float64{68, 60, 52}
+
+// Render draws the card
+func Render(card Card) (*image.RGBA, error) {
+	f, err := loadFont()
+	if err != nil {
+		return nil, err
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(colorBackground), image.Point{}, draw.Src)
+	draw.Draw(dst, image.Rect(0, 0, Width, accentHeight), image.NewUniform(colorAccent), image.Point{}, draw.Src)
+
+	top := padding + accentHeight
+	if drawIcon(dst, f, card, image.Pt(padding, top)) {
+		top += iconSize + 40
+	}
+	bottom := footerY - 40
+
+	// タイトル（フッターまでの残りの高さに収まるサイズで折り返す）
+	labelHeight := 0
+	if card.Label != "" {
+		labelHeight = 60
+	}
+	title, err := layoutTitle(f, card.Title, bottom-top-labelHeight)
+	if err != nil {
+		return nil, err
+	}
+	defer title.face.Close()
+
+	// 小見出しとタイトルを上下中央に配置
+	top += (bottom - top - labelHeight - title.height()) / 2
+	if card.Label != "" {
+		face, err := newFace(f, 30)
+		if err != nil {
+			return nil, err
+		}
+		drawText(dst, face, visible(face, card.Label), padding, top+30, colorAccent, false)
+		face.Close()
+		top += labelHeight
+	}
+	title.draw(dst, top)
+
+	// フッター: サイト名と日付
+	draw.Draw(dst, image.Rect(padding, footerY, Width-padding, footerY+2), image.NewUniform(colorBorder), image.Point{}, draw.Src)
+	face, err := newFace(f, 34)
+	if err != nil {
+		return nil, err
+	}
+	defer face.Close()
+	drawText(dst, face, card.Site, padding, footerY+70, colorAccent, true)
+	if !card.Date.IsZero() {
+		date := card.Date.Format("2006年01月02日")
+		x := Width - padding - font.MeasureString(face, date).Round()
+		drawText(dst, face, date, x, footerY+70, colorTextLight, false)
+	}
+	return dst, nil
+}
+
+func newFace(f *opentype.Font, size float64) (font.Face, error) {
+	return opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+}
+
+// bold は1px ずらして重ね描きする（太字フォントは同梱していない）
+func drawText(dst *image.RGBA, face font.Face, text string, x, y int, c color.Color, bold bool) {
+	d := &font.Drawer{Dst: dst, Src: image.NewUniform(c), Face: face}
+	offsets := []int{0}
+	if bold {
+		offsets = append(offsets, 1)
+	}
+	for _, dx := range offsets {
+		d.Dot = fixed.P(x+dx, y)
+		d.DrawString(text)
+	}
+}
+
+// titleLayout is a title wrapped at the chosen font size
+type titleLayout struct {
+	face  font.Face
+	size  float64
+	lines []string
+}
+
+func (t *titleLayout) lineHeight() int { return int(t.size * 1.4) }
+func (t *titleLayout) height() int     { return len(t.lines) * t.lineHeight() }
+
+func (t *titleLayout) draw(dst *image.RGBA, top int) {
+	for i, line := range t.lines {
+		drawText(dst, t.face, line, padding, top+int(t.size)+i*t.lineHeight(), colorText, true)
+	}
+}
+
+// 大きい文字サイズから順に試し、3行かつ maxHeight に収まるサイズを選ぶ。
+// 最小サイズでも収まらない場合は3行に切り詰める
+func layoutTitle(f *opentype.Font, title string, maxHeight int) (*titleLayout, error) {
+	maxWidth := fixed.I(Width - padding*2)
+	t := &titleLayout{}
+	for _, size := range titleSizes {
+		if t.face != nil {
+			t.face.Close()
+		}
+		face, err := newFace(f, size)
+		if err != nil {
+			return nil, err
+		}
+		t.face, t.size = face, size
+		t.lines = wrap(face, visible(face, title), maxWidth)
+		if len(t.lines) <= titleLines && t.height() <= maxHeight {
+			return t, nil
+		}
+	}
+	if len(t.lines) > titleLines {
+		t.lines = t.lines[:titleLines]
+		t.lines[titleLines-1] = ellipsis(t.face, t.lines[titleLines-1], maxWidth)
+	}
+	return t, nil
+}
+
+// アイコン（画像または絵文字）を描画。描画しなかった場合は false
+func drawIcon(dst *image.RGBA, f *opentype.Font, card Card, at image.Point) bool {
+	rect := image.Rectangle{Min: at, Max: at.Add(image.Pt(iconSize, iconSize))}
+
+	if card.IconFile != "" {
+		if icon, err := decodeFile(card.IconFile); err == nil {
+			draw.CatmullRom.Scale(dst, fit(icon.Bounds(), rect), icon, icon.Bounds(), draw.Over, nil)
+			return true
+		}
+	}
+
+	icon := strings.TrimSpace(card.Icon)
+	if icon == "" {
+		return false
+	}
+
+	// 同梱の絵文字画像
+	if img, ok := emojiImage(icon); ok {
+		draw.DrawMask(dst, rect, image.NewUniform(colorAccentBg), image.Point{}, &circle{rect}, rect.Min, draw.Over)
+		inner := rect.Inset(iconSize / 5)
+		draw.CatmullRom.Scale(dst, fit(img.Bounds(), inner), img, img.Bounds(), draw.Over, nil)
+		return true
+	}
+
+	// フォントにある文字はそのまま、ない絵文字はカテゴリ（なければタイトル）の頭文字で代用
+	face, err := newFace(f, 64)
+	if err != nil {
+		return false
+	}
+	defer face.Close()
+	if visible(face, icon) != icon {
+		icon = initial(face, card.Label, card.Title)
+		if icon == "" {
+			return false
+		}
+	}
+	draw.DrawMask(dst, rect, image.NewUniform(colorAccentBg), image.Point{}, &circle{rect}, rect.Min, draw.Over)
+	width := font.MeasureString(face, icon).Round()
+	metrics := face.Metrics()
+	baseline := rect.Min.Y + (iconSize+metrics.Ascent.Round()-metrics.Descent.Round())/2
+	drawText(dst, face, icon, rect.Min.X+(iconSize-width)/2, baseline, colorAccent, false)
+	return true
+}
+
+// 最初に描画できる文字（英字は大文字）
+func initial(face font.Face, texts ...string) string {
+	for _, text := range texts {
+		for _, r := range visible(face, text) {
+			if !unicode.IsSpace(r) {
+				return string(unicode.ToUpper(r))
+			}
+		}
+	}
+	return ""
+}
+
+func decodeFile(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	img, _, err := image.Decode(file)
+	return img, err
+}
+
+// 縦横比を保って box に収まる矩形（中央寄せ）
+func fit(src, box image.Rectangle) image.Rectangle {
+	scale := math.Min(float64(box.Dx())/float64(src.Dx()), float64(box.Dy())/float64(src.Dy()))
+	w, h := int(float64(src.Dx())*scale), int(float64(src.Dy())*scale)
+	min := box.Min.Add(image.Pt((box.Dx()-w)/2, (box.Dy()-h)/2))
+	return image.Rectangle{Min: min, Max: min.Add(image.Pt(w, h))}
+}
+
+// circle is an anti-aliased circular mask filling rect
+type circle struct {
+	rect image.Rectangle
+}
+
+func (c *circle) ColorModel() color.Model { return color.AlphaModel }
+func (c *circle) Bounds() image.Rectangle { return c.rect }
+func (c *circle) At(x, y int) color.Color {
+	r := float64(c.rect.Dx()) / 2
+	dx := float64(x-c.rect.Min.X) + 0.5 - r
+	dy := float64(y-c.rect.Min.Y) + 0.5 - r
+	alpha := math.Max(0, math.Min(1, r-math.Hypot(dx, dy)+0.5))
+	return color.Alpha{A: uint8(alpha * 255)}
+}
+
+// フォントにない文字（絵文字等）を除き、前後の空白を詰める
+func visible(face font.Face, text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if unicode.IsSpace(r) {
+			b.WriteRune(' ')
+			continue
+		}
+		if _, ok := face.GlyphAdvance(r); ok {
+			b.WriteRune(r)
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// 行頭に置かない文字・行末に置かない文字（簡易的な禁則処理）
+const (
+	noLineStart = "、。，．,.：:；;！!？?）)」』】〕〉》ー～ぁぃぅぇぉっゃゅょゎァィゥェォッャュョヮヵヶ・…"
+	noLineEnd   = "（(「『【〔〈《"
+)
+
+// 幅に収まるように折り返す。英数字の単語は途中で切らない（1行に収まらない場合を除く）
+func wrap(face font.Face, text string, maxWidth fixed.Int26_6) []string {
+	var lines []string
+	line := ""
+	for _, token := range tokenize(face, text, maxWidth) {
+		if line == "" && token == " " {
+			continue
+		}
+		if line == "" || font.MeasureString(face, line+token) <= maxWidth {
+			line += token
+			continue
+		}
+		// 句読点・閉じ括弧が行頭に来る場合と、開き括弧が行末に残る場合は直前の文字ごと次の行へ送る
+		next := token
+		last, size := utf8.DecodeLastRuneInString(line)
+		if (strings.Contains(noLineStart, token) || strings.ContainsRune(noLineEnd, last)) && len(line) > size {
+			line, next = line[:len(line)-size], string(last)+token
+		}
+		lines = append(lines, strings.TrimRight(line, " "))
+		line = strings.TrimLeft(next, " ")
+	}
+	if line = strings.TrimRight(line, " "); line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// 英数字の並びを1単語、それ以外は1文字ずつに分ける
+func tokenize(face font.Face, text string, maxWidth fixed.Int26_6) []string {
+	var tokens []string
+	var word []rune
+	flush := func() {
+		if len(word) == 0 {
+			return
+		}
+		if font.MeasureString(face, string(word)) > maxWidth {
+			for _, r := range word {
+				tokens = append(tokens, string(r))
+			}
+		} else {
+			tokens = append(tokens, string(word))
+		}
+		word = word[:0]
+	}
+	for _, r := range text {
+		if r < unicode.MaxASCII && r != ' ' && !strings.ContainsRune(noLineEnd+noLineStart, r) {
+			word = append(word, r)
+			continue
+		}
+		flush()
+		tokens = append(tokens, string(r))
+	}
+	flush()
+	return tokens
+}
+
+// 末尾を「…」にして幅に収める
+func ellipsis(face font.Face, line string, maxWidth fixed.Int26_6) string {
+	runes := []rune(line)
+	for len(runes) > 0 && font.MeasureString(face, string(runes)+"…") > maxWidth {
+		runes = runes[:len(runes)-1]
+	}
+	return strings.TrimRight(string(runes), " ") + "…"
+}
@@ -0,0 +1,78 @@
+package ogimage
+
+import (
+	"image"
+	"io/fs"
+	"testing"
+	"time"
+)
+
+// アイコンの位置（Render と同じ配置）
+var iconRect = image.Rect(padding, padding+accentHeight, padding+iconSize, padding+accentHeight+iconSize)
+
+// 矩形内で背景色・アイコンの背景色以外の画素の数
+func inkPixels(img *image.RGBA, rect image.Rectangle) (ink int, accent int) {
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			c := img.RGBAAt(x, y)
+			switch c {
+			case colorBackground, colorAccentBg:
+			case colorAccent:
+				accent++
+				ink++
+			default:
+				ink++
+			}
+		}
+	}
+	return ink, accent
+}
+
+func renderCard(t *testing.T, icon string) *image.RGBA {
+	t.Helper()
+	img, err := Render(Card{Title: "OGP画像のテスト", Icon: icon, Label: "dev", Site: "infoHiroki", Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return img
+}
+
+func TestRenderDrawsEmojiIcon(t *testing.T) {
+	for _, icon := range []string{"📝", "🤖", "🗂️"} {
+		ink, _ := inkPixels(renderCard(t, icon), iconRect.Inset(iconSize/4))
+		if ink < 500 {
+			t.Errorf("icon %s: %d pixels drawn in the icon area, want an emoji", icon, ink)
+		}
+	}
+}
+
+func TestRenderFallsBackToLabelInitial(t *testing.T) {
+	// 同梱していない絵文字はカテゴリの頭文字（アクセント色）
+	if _, ok := emojiImage("🦩"); ok {
+		t.Fatal("test emoji is bundled; pick another one")
+	}
+	_, accent := inkPixels(renderCard(t, "🦩"), iconRect)
+	if accent < 100 {
+		t.Errorf("%d accent pixels in the icon area, want the label initial", accent)
+	}
+}
+
+func TestBundledEmojiDecode(t *testing.T) {
+	names, err := fs.Glob(emojiFiles, "emoji/*.png")
+	if err != nil || len(names) == 0 {
+		t.Fatalf("no bundled emoji: %v", err)
+	}
+	for _, name := range names {
+		file, err := emojiFiles.Open(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := image.Decode(file); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+		file.Close()
+	}
+	if emojiFileName("🗂️") != "emoji/1f5c2.png" {
+		t.Errorf("emojiFileName ignores U+FE0F: %s", emojiFileName("🗂️"))
+	}
+}
@@ -0,0 +1,38 @@
+package ogimage
+
+import (
+	"embed"
+	"fmt"
+	"image"
+	"strings"
+)
+
+// 記事アイコンに使う絵文字の画像（Noto Color Emoji。M+ 1p には絵文字がない）
+//
+//go:embed emoji/*.png
+var emojiFiles embed.FS
+
+// 絵文字の画像ファイル名（コードポイントの16進数を - でつなぐ。異体字セレクタ U+FE0F は除く）
+func emojiFileName(emoji string) string {
+	var parts []string
+	for _, r := range emoji {
+		if r != 0xFE0F {
+			parts = append(parts, fmt.Sprintf("%x", r))
+		}
+	}
+	return "emoji/" + strings.Join(parts, "-") + ".png"
+}
+
+// 同梱の絵文字画像。なければ false
+func emojiImage(emoji string) (image.Image, bool) {
+	file, err := emojiFiles.Open(emojiFileName(emoji))
+	if err != nil {
+		return nil, false
+	}
+	defer file.Close()
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, false
+	}
+	return img, true
+}
@@ -0,0 +1,281 @@
+// Package redirects maps old URLs to their current location.
+//
+// Rules come from a YAML file (exact, prefix and regex matches) and from the
+// aliases of posts. A Table is immutable; build a new one on every reload.
+package redirects
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Match types
+const (
+	Exact  = "exact"
+	Prefix = "prefix"
+	Regex  = "regex"
+)
+
+// リダイレクトを辿る回数の上限（これを超える連鎖はループとみなす）
+const maxHops = 10
+
+// Rule redirects requests whose path matches From to To.
+// With status 410 the page is reported as gone and To is unused.
+type Rule struct {
+	From   string `yaml:"from" json:"from"`
+	To     string `yaml:"to" json:"to,omitempty"`
+	Type   string `yaml:"type" json:"type"`     // exact（既定）/ prefix / regex
+	Status int    `yaml:"status" json:"status"` // 301（既定）/ 302 / 410
+	Source string `yaml:"-" json:"source"`      // 定義元（ファイル名または記事のパス）
+
+	pattern *regexp.Regexp
+}
+
+// rulesFile is the layout of the redirects file
+type rulesFile struct {
+	Redirects []Rule `yaml:"redirects"`
+}
+
+// LoadFile reads rules from a YAML file; a missing file yields no rules
+func LoadFile(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file rulesFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for i := range file.Redirects {
+		file.Redirects[i].Source = path
+	}
+	return file.Redirects, nil
+}
+
+// Table is a validated set of rules
+type Table struct {
+	rules   []Rule
+	exact   map[string]int // From → rules のインデックス
+	prefix  []int          // From の長い順
+	regex   []int          // 定義順
+	invalid []error
+	known   []string // サイトが応答するパス（正規表現ルールの検証用）
+}
+
+// New validates rules in order and keeps the valid ones.
+// served reports paths the site already answers (a rule for such a path would never fire);
+// it may be nil. known lists such paths so regex rules can be checked against them.
+// Rejected rules are reported by Errors.
+func New(rules []Rule, served func(path string) bool, known []string) *Table {
+	t := &Table{exact: map[string]int{}, known: known}
+	for _, rule := range rules {
+		if err := t.add(rule, served); err != nil {
+			t.invalid = append(t.invalid, fmt.Errorf("%s: %s: %w", rule.Source, rule.From, err))
+		}
+	}
+	return t
+}
+
+func (t *Table) add(rule Rule, served func(path string) bool) error {
+	if rule.Type == "" {
+		rule.Type = Exact
+	}
+	if rule.Status == 0 {
+		rule.Status = http.StatusMovedPermanently
+	}
+
+	switch rule.Status {
+	case http.StatusMovedPermanently, http.StatusFound:
+		if rule.To == "" {
+			return fmt.Errorf("転送先（to）がありません")
+		}
+	case http.StatusGone:
+		rule.To = ""
+	default:
+		return fmt.Errorf("ステータス %d は使えません（301 / 302 / 410）", rule.Status)
+	}
+
+	switch rule.Type {
+	case Exact, Prefix:
+		if !strings.HasPrefix(rule.From, "/") {
+			return fmt.Errorf("from は / で始まるパスで指定してください")
+		}
+		if served != nil && served(rule.From) {
+			return fmt.Errorf("既存のページと重なっています")
+		}
+	case Regex:
+		pattern, err := regexp.Compile("^(?:" + rule.From + ")$")
+		if err != nil {
+			return fmt.Errorf("正規表現が不正です: %w", err)
+		}
+		rule.pattern = pattern
+		for _, path := range t.known {
+			if pattern.MatchString(path) {
+				return fmt.Errorf("既存のページ %s と重なっています", path)
+			}
+		}
+	default:
+		return fmt.Errorf("type %q は使えません（exact / prefix / regex）", rule.Type)
+	}
+
+	if rule.Type == Exact {
+		if i, ok := t.exact[rule.From]; ok {
+			return fmt.Errorf("%s の定義と重複しています", t.rules[i].Source)
+		}
+	}
+
+	t.rules = append(t.rules, rule)
+	i := len(t.rules) - 1
+	switch rule.Type {
+	case Exact:
+		t.exact[rule.From] = i
+	case Prefix:
+		t.prefix = append(t.prefix, i)
+		sort.SliceStable(t.prefix, func(a, b int) bool {
+			return len(t.rules[t.prefix[a]].From) > len(t.rules[t.prefix[b]].From)
+		})
+	case Regex:
+		t.regex = append(t.regex, i)
+	}
+
+	// 追加したルールから辿ってループするなら取り消す（正規表現は一致するパスの例から辿る）
+	starts := []string{rule.From}
+	if rule.Type == Regex {
+		starts = t.samples(rule)
+	}
+	for _, path := range starts {
+		if err := t.checkLoop(path); err != nil {
+			t.remove(i)
+			return err
+		}
+	}
+	return nil
+}
+
+// 正規表現に一致するパスの例（既存ルールの転送元・転送先と、正規表現の固定部分から作ったパス）
+func (t *Table) samples(rule Rule) []string {
+	var candidates []string
+	// 固定部分は ^ を付けない形で取り出す（付けると取り出せない場合がある）
+	if unanchored, err := regexp.Compile(rule.From); err == nil {
+		prefix, _ := unanchored.LiteralPrefix()
+		candidates = append(candidates, prefix, prefix+"x", prefix+"x/", prefix+"x.html")
+	}
+	for _, rule := range t.rules {
+		if rule.Type != Regex {
+			candidates = append(candidates, rule.From)
+		}
+		if path, ok := localPath(rule.To); ok && !strings.Contains(rule.To, "$") {
+			candidates = append(candidates, path)
+		}
+	}
+
+	var paths []string
+	for _, path := range candidates {
+		if rule.pattern.MatchString(path) {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// 最後に追加したルールを取り除く
+func (t *Table) remove(i int) {
+	rule := t.rules[i]
+	switch rule.Type {
+	case Exact:
+		delete(t.exact, rule.From)
+	case Prefix:
+		for j, k := range t.prefix {
+			if k == i {
+				t.prefix = append(t.prefix[:j], t.prefix[j+1:]...)
+				break
+			}
+		}
+	case Regex:
+		t.regex = t.regex[:len(t.regex)-1]
+	}
+	t.rules = t.rules[:i]
+}
+
+// path から転送先を辿り、同じパスに戻る・連鎖が長すぎる場合はエラー
+func (t *Table) checkLoop(path string) error {
+	chain := []string{path}
+	seen := map[string]bool{path: true}
+	for len(chain) <= maxHops {
+		target, status, ok := t.Match(path)
+		if !ok || status == http.StatusGone {
+			return nil
+		}
+		next, local := localPath(target)
+		if !local {
+			return nil
+		}
+		chain = append(chain, next)
+		if seen[next] {
+			return fmt.Errorf("リダイレクトがループしています: %s", strings.Join(chain, " → "))
+		}
+		seen[next] = true
+		path = next
+	}
+	return fmt.Errorf("リダイレクトの連鎖が長すぎます: %s", strings.Join(chain, " → "))
+}
+
+// サイト内の転送先ならそのパス（クエリ・フラグメントを除く）
+func localPath(target string) (string, bool) {
+	u, err := url.Parse(target)
+	if err != nil || u.Scheme != "" || u.Host != "" {
+		return "", false
+	}
+	return u.Path, strings.HasPrefix(u.Path, "/")
+}
+
+// Match returns where path redirects to; the target is empty for 410.
+// Exact rules win over prefix rules (longest first), which win over regex rules (in order).
+func (t *Table) Match(path string) (target string, status int, ok bool) {
+	if i, found := t.exact[path]; found {
+		return t.rules[i].To, t.rules[i].Status, true
+	}
+	for _, i := range t.prefix {
+		rule := t.rules[i]
+		// 接頭辞はパスの区切りで一致した場合のみ（/old は /older-post に一致しない）
+		if rest, found := strings.CutPrefix(path, rule.From); found && (rest == "" || strings.HasSuffix(rule.From, "/") || strings.HasPrefix(rest, "/")) {
+			if rule.Status == http.StatusGone {
+				return "", rule.Status, true
+			}
+			return rule.To + rest, rule.Status, true
+		}
+	}
+	for _, i := range t.regex {
+		rule := t.rules[i]
+		match := rule.pattern.FindStringSubmatchIndex(path)
+		if match == nil {
+			continue
+		}
+		if rule.Status == http.StatusGone {
+			return "", rule.Status, true
+		}
+		return string(rule.pattern.ExpandString(nil, rule.To, path, match)), rule.Status, true
+	}
+	return "", 0, false
+}
+
+// Rules returns the valid rules in definition order (type and status filled in)
+func (t *Table) Rules() []Rule {
+	return append([]Rule(nil), t.rules...)
+}
+
+// Errors returns why rules were rejected
+func (t *Table) Errors() []error {
+	return t.invalid
+}
@@ -0,0 +1,80 @@
+package redirects
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestPrefixMatchesWholeSegments(t *testing.T) {
+	table := New([]Rule{{From: "/old", To: "/new", Type: Prefix}}, nil, nil)
+
+	tests := []struct {
+		path   string
+		target string
+		ok     bool
+	}{
+		{"/old", "/new", true},
+		{"/old/post", "/new/post", true},
+		{"/older-post", "", false},
+		{"/oldies", "", false},
+	}
+	for _, tt := range tests {
+		target, _, ok := table.Match(tt.path)
+		if ok != tt.ok || target != tt.target {
+			t.Errorf("Match(%q) = %q, %v; want %q, %v", tt.path, target, ok, tt.target, tt.ok)
+		}
+	}
+}
+
+func TestPrefixWithTrailingSlash(t *testing.T) {
+	table := New([]Rule{{From: "/docs/", To: "/blog/", Type: Prefix}}, nil, nil)
+	if target, _, ok := table.Match("/docs/intro"); !ok || target != "/blog/intro" {
+		t.Errorf("Match(/docs/intro) = %q, %v", target, ok)
+	}
+}
+
+func TestRegexRejectsExistingRoutes(t *testing.T) {
+	known := []string{"/", "/blog", "/blog/2024-05-19-ai-prompt-design-guide"}
+	table := New([]Rule{
+		{From: `/blog/(.+)`, To: "/posts/$1", Type: Regex},
+		{From: `/html-files/([^/]+?)(\.html)?`, To: "/blog/$1", Type: Regex},
+	}, nil, known)
+
+	if len(table.Rules()) != 1 || table.Rules()[0].From != `/html-files/([^/]+?)(\.html)?` {
+		t.Fatalf("Rules() = %+v, want only the html-files rule", table.Rules())
+	}
+	if errs := table.Errors(); len(errs) != 1 || !strings.Contains(errs[0].Error(), "/blog/2024-05-19-ai-prompt-design-guide") {
+		t.Errorf("Errors() = %v", errs)
+	}
+}
+
+func TestRegexLoops(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []Rule
+	}{
+		{"self", []Rule{{From: `/a/(.*)`, To: "/a/$1", Type: Regex}}},
+		{"via exact", []Rule{
+			{From: "/b", To: "/c/b"},
+			{From: `/c/(.*)`, To: "/$1", Type: Regex},
+		}},
+	}
+	for _, tt := range tests {
+		table := New(tt.rules, nil, nil)
+		if len(table.Errors()) != 1 || !strings.Contains(table.Errors()[0].Error(), "ループ") {
+			t.Errorf("%s: Errors() = %v, want a loop error", tt.name, table.Errors())
+		}
+	}
+}
+
+func TestRegexRedirect(t *testing.T) {
+	table := New([]Rule{{From: `/html-files/([^/]+?)(\.html)?`, To: "/blog/$1", Type: Regex}}, nil, nil)
+	if errs := table.Errors(); len(errs) != 0 {
+		t.Fatalf("Errors() = %v", errs)
+	}
+	target, status, ok := table.Match("/html-files/hello.html")
+	if !ok || target != "/blog/hello" || status != http.StatusMovedPermanently {
+		t.Errorf("Match = %q, %d, %v", target, status, ok)
+	}
+}
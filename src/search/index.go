@@ -0,0 +1,181 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// BM25 parameters
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Field weights: a hit in the title counts more than one in the body
+const (
+	WeightTitle       = 3.0
+	WeightDescription = 2.0
+	WeightTags        = 2.0
+	WeightHeadings    = 2.0
+	WeightBody        = 1.0
+)
+
+// Document is the searchable text of one post
+type Document struct {
+	ID          string
+	Title       string
+	Description string
+	Tags        string
+	Headings    string
+	Body        string
+}
+
+// Result is a matched document ID with its BM25 score
+type Result struct {
+	ID    string
+	Score float64
+}
+
+type posting struct {
+	doc int
+	tf  float64 // フィールド重み付き出現回数
+}
+
+// Index is an immutable inverted index over a set of documents
+type Index struct {
+	ids       []string
+	lengths   []float64 // フィールド重み付き文書長
+	avgLength float64
+	postings  map[string][]posting
+}
+
+// NewIndex builds an index over docs
+func NewIndex(docs []Document) *Index {
+	idx := &Index{
+		ids:      make([]string, len(docs)),
+		lengths:  make([]float64, len(docs)),
+		postings: map[string][]posting{},
+	}
+
+	var total float64
+	for i, doc := range docs {
+		idx.ids[i] = doc.ID
+
+		freqs := map[string]float64{}
+		var length float64
+		for _, field := range []struct {
+			text   string
+			weight float64
+		}{
+			{doc.Title, WeightTitle},
+			{doc.Description, WeightDescription},
+			{doc.Tags, WeightTags},
+			{doc.Headings, WeightHeadings},
+			{doc.Body, WeightBody},
+		} {
+			for _, token := range Tokenize(field.text) {
+				freqs[token] += field.weight
+				length += field.weight
+			}
+		}
+
+		for token, tf := range freqs {
+			idx.postings[token] = append(idx.postings[token], posting{doc: i, tf: tf})
+		}
+		idx.lengths[i] = length
+		total += length
+	}
+
+	if len(docs) > 0 {
+		idx.avgLength = total / float64(len(docs))
+	}
+	return idx
+}
+
+// Search returns documents containing every query term, highest BM25 score first
+func (idx *Index) Search(query string) []Result {
+	terms := uniqueTerms(Tokenize(query))
+	if len(terms) == 0 || len(idx.ids) == 0 {
+		return nil
+	}
+
+	scores := map[int]float64{}
+	matched := map[int]int{}
+	for _, term := range terms {
+		// 1文字の漢字・かなはその文字を含むbi-gramすべてに展開
+		for doc, score := range idx.scoreTerm(term) {
+			scores[doc] += score
+			matched[doc]++
+		}
+	}
+
+	results := make([]Result, 0, len(scores))
+	for doc, score := range scores {
+		// AND検索：すべての語を含む文書のみ
+		if matched[doc] < len(terms) {
+			continue
+		}
+		results = append(results, Result{ID: idx.ids[doc], Score: score})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].ID > results[j].ID
+	})
+	return results
+}
+
+// 1語ぶんのBM25スコアを文書ごとに計算
+func (idx *Index) scoreTerm(term string) map[int]float64 {
+	lists := [][]posting{idx.postings[term]}
+	if r, _ := utf8.DecodeRuneInString(term); utf8.RuneCountInString(term) == 1 && isCJK(r) {
+		for key, list := range idx.postings {
+			if key != term && strings.ContainsRune(key, r) {
+				lists = append(lists, list)
+			}
+		}
+	}
+
+	// 展開した語は1つの語として扱う
+	tfs := map[int]float64{}
+	for _, list := range lists {
+		for _, p := range list {
+			tfs[p.doc] += p.tf
+		}
+	}
+	if len(tfs) == 0 {
+		return nil
+	}
+
+	n := float64(len(idx.ids))
+	df := float64(len(tfs))
+	idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+
+	scores := make(map[int]float64, len(tfs))
+	for doc, tf := range tfs {
+		norm := bm25K1 * (1 - bm25B + bm25B*idx.lengths[doc]/idx.avgLength)
+		scores[doc] = idf * tf * (bm25K1 + 1) / (tf + norm)
+	}
+	return scores
+}
+
+func uniqueTerms(tokens []string) []string {
+	seen := map[string]bool{}
+	var terms []string
+	for _, token := range tokens {
+		if !seen[token] {
+			seen[token] = true
+			terms = append(terms, token)
+		}
+	}
+	return terms
+}
+
+// Len returns the number of indexed documents
+func (idx *Index) Len() int {
+	return len(idx.ids)
+}
@@ -0,0 +1,87 @@
+package search
+
+import (
+	"testing"
+)
+
+func indexTestDocs() []Document {
+	return []Document{
+		{ID: "title", Title: "Go言語の並行処理", Body: "ゴルーチンとチャネルを使います。"},
+		{ID: "body", Title: "日記", Body: "今日はGo言語の並行処理について少し調べました。"},
+		{ID: "tags", Title: "Docker入門", Tags: "go docker", Body: "コンテナを使います。"},
+		{ID: "other", Title: "Notion入門", Body: "データベースでタスクを管理します。"},
+	}
+}
+
+func resultIDs(results []Result) []string {
+	ids := make([]string, len(results))
+	for i, r := range results {
+		ids[i] = r.ID
+	}
+	return ids
+}
+
+func TestIndexSearchWeightsTitle(t *testing.T) {
+	idx := NewIndex(indexTestDocs())
+	results := idx.Search("並行処理")
+	if len(results) != 2 || results[0].ID != "title" || results[1].ID != "body" {
+		t.Fatalf("Search(並行処理) = %v, want [title body]", resultIDs(results))
+	}
+	if results[0].Score <= results[1].Score {
+		t.Errorf("title hit scored %f, body hit %f", results[0].Score, results[1].Score)
+	}
+}
+
+func TestIndexSearchRequiresEveryTerm(t *testing.T) {
+	idx := NewIndex(indexTestDocs())
+	if got := resultIDs(idx.Search("go docker")); len(got) != 1 || got[0] != "tags" {
+		t.Errorf("Search(go docker) = %v, want [tags]", got)
+	}
+	if got := idx.Search("go notion"); len(got) != 0 {
+		t.Errorf("Search(go notion) = %v, want none", resultIDs(got))
+	}
+}
+
+func TestIndexSearchSingleCJKCharacter(t *testing.T) {
+	idx := NewIndex(indexTestDocs())
+	// 1文字の語はその文字を含むbi-gramに展開
+	got := resultIDs(idx.Search("処"))
+	if len(got) != 2 {
+		t.Errorf("Search(処) = %v, want title and body", got)
+	}
+	if got := idx.Search("猫"); len(got) != 0 {
+		t.Errorf("Search(猫) = %v, want none", resultIDs(got))
+	}
+}
+
+func TestIndexSearchRareTermScoresHigher(t *testing.T) {
+	idx := NewIndex([]Document{
+		{ID: "a", Body: "go rust"},
+		{ID: "b", Body: "go"},
+		{ID: "c", Body: "go"},
+	})
+	rare := idx.Search("rust")
+	common := idx.Search("go")
+	if len(rare) != 1 || len(common) != 3 {
+		t.Fatalf("rust: %v, go: %v", resultIDs(rare), resultIDs(common))
+	}
+	// 多くの文書に出る語ほど IDF が低い
+	for _, r := range common {
+		if r.Score >= rare[0].Score {
+			t.Errorf("common term scored %f for %s, rare term %f", r.Score, r.ID, rare[0].Score)
+		}
+	}
+	// 長い文書は低く、同点は ID の降順
+	if got := resultIDs(common); got[0] != "c" || got[1] != "b" || got[2] != "a" {
+		t.Errorf("ties ordered %v", got)
+	}
+}
+
+func TestIndexSearchEmpty(t *testing.T) {
+	if got := NewIndex(nil).Search("go"); got != nil {
+		t.Errorf("empty index returned %v", got)
+	}
+	if got := NewIndex(indexTestDocs()).Search("、。 !"); got != nil {
+		t.Errorf("query without terms returned %v", got)
+	}
+}
@@ -0,0 +1,157 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Field weights for related-post vectors; tags describe the topic best
+const (
+	relatedWeightTitle       = 3.0
+	relatedWeightTags        = 3.0
+	relatedWeightDescription = 2.0
+	relatedWeightHeadings    = 1.5
+	relatedWeightBody        = 1.0
+)
+
+// Terms appearing in more than this share of documents carry no topical signal
+const relatedMaxDocFreq = 0.5
+
+// TermWeight is one term's contribution to a similarity score
+type TermWeight struct {
+	Term   string  `json:"term"`
+	Weight float64 `json:"weight"`
+}
+
+// Related holds precomputed TF-IDF similarities between documents
+type Related struct {
+	vectors map[string]map[string]float64 // 文書ID → 正規化済みTF-IDFベクトル
+	similar map[string][]Result           // 文書ID → 類似度の高い順
+}
+
+// NewRelated computes, for every document, up to limit most similar documents
+// whose cosine similarity is at least minScore.
+func NewRelated(docs []Document, limit int, minScore float64) *Related {
+	r := &Related{
+		vectors: make(map[string]map[string]float64, len(docs)),
+		similar: make(map[string][]Result, len(docs)),
+	}
+
+	// 各文書のフィールド重み付き出現回数
+	freqs := make([]map[string]float64, len(docs))
+	docFreq := map[string]int{}
+	for i, doc := range docs {
+		freqs[i] = map[string]float64{}
+		for _, field := range []struct {
+			text   string
+			weight float64
+		}{
+			{doc.Title, relatedWeightTitle},
+			{doc.Tags, relatedWeightTags},
+			{doc.Description, relatedWeightDescription},
+			{doc.Headings, relatedWeightHeadings},
+			{doc.Body, relatedWeightBody},
+		} {
+			for _, token := range Tokenize(field.text) {
+				if topical(token) {
+					freqs[i][token] += field.weight
+				}
+			}
+		}
+		for term := range freqs[i] {
+			docFreq[term]++
+		}
+	}
+
+	n := float64(len(docs))
+	postings := map[string][]int{}
+	for i, doc := range docs {
+		vector := map[string]float64{}
+		var norm float64
+		for term, tf := range freqs[i] {
+			df := float64(docFreq[term])
+			// 1文書にしか出ない語・ほとんどの文書に出る語は類似度に寄与しない
+			if df < 2 || df/n > relatedMaxDocFreq {
+				continue
+			}
+			w := (1 + math.Log(tf)) * math.Log(n/df)
+			vector[term] = w
+			norm += w * w
+		}
+		norm = math.Sqrt(norm)
+		for term := range vector {
+			vector[term] /= norm
+			postings[term] = append(postings[term], i)
+		}
+		r.vectors[doc.ID] = vector
+	}
+
+	for i, doc := range docs {
+		vector := r.vectors[doc.ID]
+		scores := map[int]float64{}
+		for term, w := range vector {
+			for _, j := range postings[term] {
+				if j != i {
+					scores[j] += w * r.vectors[docs[j].ID][term]
+				}
+			}
+		}
+
+		var results []Result
+		for j, score := range scores {
+			if score >= minScore && docs[j].ID != doc.ID {
+				results = append(results, Result{ID: docs[j].ID, Score: score})
+			}
+		}
+		sort.Slice(results, func(a, b int) bool {
+			if results[a].Score != results[b].Score {
+				return results[a].Score > results[b].Score
+			}
+			return results[a].ID > results[b].ID
+		})
+		if len(results) > limit {
+			results = results[:limit]
+		}
+		r.similar[doc.ID] = results
+	}
+
+	return r
+}
+
+// ひらがなを含む bi-gram（助詞・送り仮名・「思い」「しっ」など）は話題を表さないので除く
+func topical(term string) bool {
+	for _, r := range term {
+		if unicode.Is(unicode.Hiragana, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Similar returns the precomputed similar documents for id, most similar first
+func (r *Related) Similar(id string) []Result {
+	return r.similar[id]
+}
+
+// SharedTerms explains a similarity score: the n terms contributing most to it
+func (r *Related) SharedTerms(a, b string, n int) []TermWeight {
+	va, vb := r.vectors[a], r.vectors[b]
+	var terms []TermWeight
+	for term, w := range va {
+		if other, ok := vb[term]; ok {
+			terms = append(terms, TermWeight{Term: term, Weight: w * other})
+		}
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if terms[i].Weight != terms[j].Weight {
+			return terms[i].Weight > terms[j].Weight
+		}
+		return strings.Compare(terms[i].Term, terms[j].Term) < 0
+	})
+	if len(terms) > n {
+		terms = terms[:n]
+	}
+	return terms
+}
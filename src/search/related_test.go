@@ -0,0 +1,48 @@
+package search
+
+import (
+	"testing"
+	"unicode"
+)
+
+func relatedTestDocs() []Document {
+	// 同じ話題の記事の組（prompt-*・notion-*）と、言い回しだけが prompt-design に似た日記
+	return []Document{
+		{ID: "prompt-design", Title: "プロンプト設計ガイド", Body: "プロンプトの設計では役割と出力形式を指定します。たまにしっかり思い出して行きましょう。"},
+		{ID: "prompt-storage", Title: "プロンプトの保存方法", Body: "よく使うプロンプトは出力形式ごとに保存します。"},
+		{ID: "diary", Title: "週末の散歩", Body: "たまにしっかり思い出して行きましょう。たまにしっかり思い出して行きたいです。"},
+		{ID: "diary-2", Title: "朝の習慣", Body: "たまにしっかり思い出して行きます。"},
+		{ID: "notion-db", Title: "Notionデータベース入門", Body: "Notionのデータベースでタスクを管理します。"},
+		{ID: "notion-tasks", Title: "Notionでタスク管理", Body: "データベースのビューでタスクを整理します。"},
+	}
+}
+
+func TestRelatedFindsTopicalPair(t *testing.T) {
+	related := NewRelated(relatedTestDocs(), 3, 0.05)
+
+	for id, want := range map[string]string{
+		"prompt-design": "prompt-storage",
+		"notion-db":     "notion-tasks",
+	} {
+		similar := related.Similar(id)
+		if len(similar) == 0 || similar[0].ID != want {
+			t.Errorf("Similar(%q) = %+v, want %s first", id, similar, want)
+		}
+	}
+	for _, result := range related.Similar("prompt-design") {
+		if result.ID == "diary" || result.ID == "diary-2" {
+			t.Errorf("Similar(prompt-design) includes %s, which only shares kana phrasing", result.ID)
+		}
+	}
+}
+
+func TestRelatedIgnoresHiraganaBigrams(t *testing.T) {
+	related := NewRelated(relatedTestDocs(), 3, 0)
+	for _, term := range related.SharedTerms("prompt-design", "diary", 10) {
+		for _, r := range term.Term {
+			if unicode.Is(unicode.Hiragana, r) {
+				t.Errorf("shared term %q contains hiragana", term.Term)
+			}
+		}
+	}
+}
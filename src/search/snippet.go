@@ -0,0 +1,128 @@
+package search
+
+import (
+	"html/template"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Match is a highlighted range within Snippet.Text, in characters (runes)
+type Match struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// Snippet is an excerpt of a document around one or more query hits
+type Snippet struct {
+	Text    string  `json:"text"`
+	Matches []Match `json:"matches"`
+}
+
+// HTML returns the snippet escaped, with every match wrapped in <mark>
+func (s Snippet) HTML() template.HTML {
+	runes := []rune(s.Text)
+	var b strings.Builder
+	pos := 0
+	for _, m := range s.Matches {
+		b.WriteString(template.HTMLEscapeString(string(runes[pos:m.Start])))
+		b.WriteString("<mark>")
+		b.WriteString(template.HTMLEscapeString(string(runes[m.Start:m.End])))
+		b.WriteString("</mark>")
+		pos = m.End
+	}
+	b.WriteString(template.HTMLEscapeString(string(runes[pos:])))
+	return template.HTML(b.String())
+}
+
+// Snippets extracts up to max excerpts of text around occurrences of the query words.
+// Each excerpt keeps about radius characters of context on both sides of its first hit.
+func Snippets(text string, query string, max int, radius int) []Snippet {
+	runes := []rune(text)
+	hits := findMatches(runes, query)
+	if len(hits) == 0 || max <= 0 {
+		return nil
+	}
+
+	var snippets []Snippet
+	for i := 0; i < len(hits) && len(snippets) < max; {
+		start := hits[i].Start - radius
+		if start < 0 {
+			start = 0
+		}
+		end := hits[i].End + radius
+		if end > len(runes) {
+			end = len(runes)
+		}
+
+		// 窓に収まる後続のヒットも同じ抜粋にまとめる
+		snippet := Snippet{}
+		for ; i < len(hits) && hits[i].End <= end; i++ {
+			snippet.Matches = append(snippet.Matches, Match{Start: hits[i].Start - start, End: hits[i].End - start})
+		}
+
+		prefix, suffix := "", ""
+		if start > 0 {
+			prefix = "…"
+		}
+		if end < len(runes) {
+			suffix = "…"
+		}
+		offset := len([]rune(prefix))
+		for j := range snippet.Matches {
+			snippet.Matches[j].Start += offset
+			snippet.Matches[j].End += offset
+		}
+		snippet.Text = prefix + string(runes[start:end]) + suffix
+		snippets = append(snippets, snippet)
+	}
+
+	return snippets
+}
+
+// 正規化したテキスト上でクエリ語を探し、元テキストの位置で返す
+func findMatches(runes []rune, query string) []Match {
+	// 正規化後の各文字が元テキストの何文字目に由来するかを記録
+	var normalized []rune
+	var origin []int
+	for i, r := range runes {
+		for _, n := range strings.ToLower(norm.NFKC.String(string(r))) {
+			normalized = append(normalized, n)
+			origin = append(origin, i)
+		}
+	}
+
+	var matches []Match
+	for _, word := range strings.Fields(strings.ToLower(norm.NFKC.String(query))) {
+		needle := []rune(word)
+		for i := 0; i+len(needle) <= len(normalized); i++ {
+			if runesEqual(normalized[i:i+len(needle)], needle) {
+				matches = append(matches, Match{Start: origin[i], End: origin[i+len(needle)-1] + 1})
+			}
+		}
+	}
+
+	// 位置順に並べ、重なる一致は結合
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Start < matches[j].Start })
+	var merged []Match
+	for _, m := range matches {
+		if n := len(merged); n > 0 && m.Start <= merged[n-1].End {
+			if m.End > merged[n-1].End {
+				merged[n-1].End = m.End
+			}
+			continue
+		}
+		merged = append(merged, m)
+	}
+	return merged
+}
+
+func runesEqual(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,84 @@
+package search
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSnippetHTMLEscapes(t *testing.T) {
+	tests := []struct {
+		text  string
+		query string
+		want  string
+	}{
+		{"<script>alert(1)</script>", "script", "&lt;<mark>script</mark>&gt;alert(1)&lt;/<mark>script</mark>&gt;"},
+		{"a & b", "&", "a <mark>&amp;</mark> b"},
+		{`say "hi" <b>`, `"hi"`, `say <mark>&#34;hi&#34;</mark> &lt;b&gt;`},
+		// クエリ側のタグも本文どおりエスケープされる
+		{"x <mark>y</mark>", "<mark>", "x <mark>&lt;mark&gt;</mark>y&lt;/mark&gt;"},
+		{"<img src=x onerror=alert(1)>", "onerror", "&lt;img src=x <mark>onerror</mark>=alert(1)&gt;"},
+	}
+	for _, tt := range tests {
+		snippets := Snippets(tt.text, tt.query, 1, 100)
+		if len(snippets) != 1 {
+			t.Fatalf("Snippets(%q, %q) = %v", tt.text, tt.query, snippets)
+		}
+		if got := string(snippets[0].HTML()); got != tt.want {
+			t.Errorf("HTML of %q for %q = %s, want %s", tt.text, tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSnippetHTMLWithoutMatches(t *testing.T) {
+	if got := (Snippet{Text: "<b>&"}).HTML(); got != "&lt;b&gt;&amp;" {
+		t.Errorf("HTML = %s", got)
+	}
+}
+
+func TestSnippetsWindow(t *testing.T) {
+	text := strings.Repeat("あ", 20) + "検索" + strings.Repeat("い", 20) + "検索" + strings.Repeat("う", 20)
+
+	snippets := Snippets(text, "検索", 5, 5)
+	if len(snippets) != 2 {
+		t.Fatalf("%d snippets, want 2", len(snippets))
+	}
+	want := Snippet{Text: "…あああああ検索いいいいい…", Matches: []Match{{Start: 6, End: 8}}}
+	if !reflect.DeepEqual(snippets[0], want) {
+		t.Errorf("first snippet = %+v, want %+v", snippets[0], want)
+	}
+
+	// 窓に収まるヒットは1つの抜粋にまとめる
+	snippets = Snippets(text, "検索", 5, 30)
+	if len(snippets) != 1 || len(snippets[0].Matches) != 2 {
+		t.Errorf("wide window: %+v", snippets)
+	}
+
+	if got := Snippets(text, "検索", 1, 5); len(got) != 1 {
+		t.Errorf("max 1: %d snippets", len(got))
+	}
+	if got := Snippets(text, "ない", 3, 5); got != nil {
+		t.Errorf("no hit: %+v", got)
+	}
+}
+
+func TestSnippetsNormalizedMatch(t *testing.T) {
+	// 全角・大文字の本文も正規化したクエリで一致し、元の文字の範囲を返す
+	snippets := Snippets("ＧＯ言語とGoの違い", "go", 1, 100)
+	if len(snippets) != 1 {
+		t.Fatalf("snippets = %+v", snippets)
+	}
+	want := []Match{{Start: 0, End: 2}, {Start: 5, End: 7}}
+	if !reflect.DeepEqual(snippets[0].Matches, want) {
+		t.Errorf("matches = %+v, want %+v", snippets[0].Matches, want)
+	}
+	if got := string(snippets[0].HTML()); got != "<mark>ＧＯ</mark>言語と<mark>Go</mark>の違い" {
+		t.Errorf("HTML = %s", got)
+	}
+
+	// 重なる一致は結合
+	snippets = Snippets("データベース", "データ タベ", 1, 100)
+	if want := []Match{{Start: 0, End: 4}}; len(snippets) != 1 || !reflect.DeepEqual(snippets[0].Matches, want) {
+		t.Errorf("overlapping matches = %+v", snippets)
+	}
+}
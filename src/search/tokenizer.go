@@ -0,0 +1,70 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Tokenize splits text into search terms.
+// Latin letters and digits become lowercase words; runs of kanji, hiragana and
+// katakana become overlapping character bi-grams (a single character stays a uni-gram).
+func Tokenize(text string) []string {
+	// 全角英数字・半角カナを正規化
+	text = strings.ToLower(norm.NFKC.String(text))
+
+	var tokens []string
+	var word []rune
+	var cjk []rune
+
+	flushWord := func() {
+		if len(word) > 0 {
+			tokens = append(tokens, string(word))
+			word = word[:0]
+		}
+	}
+	flushCJK := func() {
+		tokens = append(tokens, ngrams(cjk, 2)...)
+		cjk = cjk[:0]
+	}
+
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			flushWord()
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushCJK()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushCJK()
+		}
+	}
+	flushWord()
+	flushCJK()
+
+	return tokens
+}
+
+// 漢字・ひらがな・カタカナ（長音記号を含む）
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) || r == 'ー' || r == '々'
+}
+
+// 文字n-gramを生成（n文字未満の場合はそのまま1トークン）
+func ngrams(runes []rune, n int) []string {
+	if len(runes) == 0 {
+		return nil
+	}
+	if len(runes) < n {
+		return []string{string(runes)}
+	}
+
+	grams := make([]string, 0, len(runes)-n+1)
+	for i := 0; i+n <= len(runes); i++ {
+		grams = append(grams, string(runes[i:i+n]))
+	}
+	return grams
+}
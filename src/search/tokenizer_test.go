@@ -0,0 +1,31 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", nil},
+		{"Go言語", []string{"go", "言語"}},
+		{"全文検索", []string{"全文", "文検", "検索"}},
+		{"検", []string{"検"}},
+		{"データベース", []string{"デー", "ータ", "タベ", "ベー", "ース"}},
+		{"人々", []string{"人々"}},
+		// 全角英数字・半角カナは正規化、英字は小文字
+		{"ＧＯ１２３ ﾃｽﾄ", []string{"go123", "テス", "スト"}},
+		// 記号と空白で区切る
+		{"Hello, World! go-lang", []string{"hello", "world", "go", "lang"}},
+		{"生成AIの導入", []string{"生成", "ai", "の導", "導入"}},
+		{"日本語。英語", []string{"日本", "本語", "英語"}},
+	}
+	for _, tt := range tests {
+		if got := Tokenize(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Tokenize(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
@@ -24,6 +24,60 @@ document.addEventListener('DOMContentLoaded', function() {
         });
     });
     
+    // コードブロックのコピーボタン（行番号は除いてコピー）
+    document.querySelectorAll('.code-block').forEach(function(block) {
+        const button = document.createElement('button');
+        button.type = 'button';
+        button.className = 'code-copy';
+        button.textContent = 'コピー';
+        button.setAttribute('aria-label', 'コードをコピー');
+        button.addEventListener('click', function() {
+            const code = block.querySelector('code').cloneNode(true);
+            code.querySelectorAll('.ln').forEach(ln => ln.remove());
+            navigator.clipboard.writeText(code.textContent).then(() => {
+                showNotification('コピーしました');
+            }).catch(() => {
+                showNotification('コピーできませんでした');
+            });
+        });
+        block.classList.add('has-copy');
+        block.appendChild(button);
+    });
+
+    // Mermaid図：図がある記事だけライブラリを読み込んで描画（失敗時は定義のテキストを表示したまま）
+    if (document.querySelector('pre.mermaid')) {
+        import('https://cdn.jsdelivr.net/npm/mermaid@11/dist/mermaid.esm.min.mjs').then(module => {
+            const mermaid = module.default;
+            mermaid.initialize({ startOnLoad: false, securityLevel: 'strict', theme: 'neutral' });
+            return mermaid.run({ querySelector: 'pre.mermaid' });
+        }).then(() => {
+            document.querySelectorAll('.diagram').forEach(diagram => diagram.classList.add('rendered'));
+        }).catch(error => {
+            console.warn('Mermaid図を描画できませんでした', error);
+        });
+    }
+
+    // 記事内目次：表示中の見出しをハイライト
+    const tocLinks = document.querySelectorAll('.post-toc a[href^="#"]');
+    if (tocLinks.length > 0 && 'IntersectionObserver' in window) {
+        const linkById = {};
+        tocLinks.forEach(link => {
+            linkById[decodeURIComponent(link.getAttribute('href').slice(1))] = link;
+        });
+        const observer = new IntersectionObserver(function(entries) {
+            entries.forEach(entry => {
+                if (entry.isIntersecting && linkById[entry.target.id]) {
+                    tocLinks.forEach(link => link.classList.remove('active'));
+                    linkById[entry.target.id].classList.add('active');
+                }
+            });
+        }, { rootMargin: '0px 0px -70% 0px' });
+        Object.keys(linkById).forEach(id => {
+            const heading = document.getElementById(id);
+            if (heading) observer.observe(heading);
+        });
+    }
+
     // 通知表示関数（アクセシビリティ対応）
     function showNotification(message) {
         const notification = document.createElement('div');
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"html"
+	"html/template"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"infohiroki-go/src/jsonld"
+	"infohiroki-go/src/models"
+)
+
+const (
+	siteName        = "infoHiroki"
+	siteDescription = "技術者が直接ヒアリング・提案。開発からコンサルまでワンストップ。中小企業・スタートアップのDX・生成AI導入を伴走支援"
+	siteEmail       = "info.hirokitakamura@gmail.com"
+)
+
+// 構造化データ（JSON-LD）のscriptタグ。テンプレートの jsonLD に渡す
+func jsonLD(objects ...interface{}) template.HTML {
+	script, err := jsonld.Script(objects...)
+	if err != nil {
+		log.Printf("⚠️ 構造化データを生成できません: %v", err)
+		return ""
+	}
+	return script
+}
+
+// サイトのロゴ
+func siteLogo() *jsonld.ImageObject {
+	return jsonld.NewImage(siteBaseURL+"/images/logo.svg", 500, 500)
+}
+
+// サイト運営者（ホームページ）
+func siteOrganization() *jsonld.Organization {
+	return &jsonld.Organization{
+		Context:       jsonld.Context,
+		Type:          "Organization",
+		ID:            siteBaseURL + "/#organization",
+		Name:          siteName,
+		AlternateName: "info Hiroki",
+		Description:   siteDescription,
+		URL:           siteBaseURL + "/",
+		Logo:          siteLogo(),
+		ContactPoint: &jsonld.ContactPoint{
+			Type:              "ContactPoint",
+			ContactType:       "customer service",
+			Email:             siteEmail,
+			URL:               siteBaseURL + "/contact",
+			AvailableLanguage: "Japanese",
+		},
+	}
+}
+
+// 記事・ブログの発行者（入れ子用）
+func sitePublisher() *jsonld.Organization {
+	return &jsonld.Organization{
+		Type: "Organization",
+		ID:   siteBaseURL + "/#organization",
+		Name: siteName,
+		URL:  siteBaseURL + "/",
+		Logo: siteLogo(),
+	}
+}
+
+// 提供サービスと料金（サービスページ）
+func siteProfessionalService() *jsonld.Organization {
+	return &jsonld.Organization{
+		Context:     jsonld.Context,
+		Type:        "ProfessionalService",
+		ID:          siteBaseURL + "/services#service",
+		Name:        siteName,
+		Description: "中小企業・スタートアップ向けDX・生成AI導入支援。エンジニアが直接ヒアリング・提案。開発からコンサルまでワンストップ対応",
+		URL:         siteBaseURL + "/services",
+		Image:       siteBaseURL + "/images/logo.svg",
+		Email:       siteEmail,
+		AreaServed:  "JP",
+		PriceRange:  "¥20,000〜¥5,000,000",
+		HasOfferCatalog: &jsonld.OfferCatalog{
+			Type: "OfferCatalog",
+			Name: "サービス・料金体系",
+			ItemListElement: []jsonld.Offer{
+				jsonld.NewOffer("技術顧問サービス", "月15時間の継続的な生成AI活用技術支援",
+					&jsonld.PriceSpecification{Type: "UnitPriceSpecification", Price: 50000, PriceCurrency: "JPY", UnitText: "月額"}),
+				jsonld.NewOffer("生成AI導入プロジェクト", "企業規模・内容に応じた生成AI導入プロジェクト",
+					&jsonld.PriceSpecification{Type: "PriceSpecification", MinPrice: 200000, MaxPrice: 5000000, PriceCurrency: "JPY"}),
+				jsonld.NewOffer("AI導入相談", "1.5時間の相談で現状分析レポート＋具体的改善提案書を提供",
+					&jsonld.PriceSpecification{Type: "PriceSpecification", Price: 20000, PriceCurrency: "JPY"}),
+			},
+		},
+	}
+}
+
+// ホーム → ブログ → ... のパンくず
+func blogBreadcrumbs(crumbs ...jsonld.Crumb) *jsonld.BreadcrumbList {
+	trail := []jsonld.Crumb{
+		{Name: "ホーム", URL: siteBaseURL + "/"},
+		{Name: "ブログ", URL: siteBaseURL + "/blog"},
+	}
+	return jsonld.NewBreadcrumbList(append(trail, crumbs...)...)
+}
+
+// ブログ一覧系ページ（path は /blog 以下のページ、name はパンくずの表示名）
+func blogListJSONLD(path string, name string) template.HTML {
+	blog := &jsonld.Blog{
+		Context:     jsonld.Context,
+		Type:        "Blog",
+		Name:        "infoHiroki ブログ",
+		Description: feedDescription,
+		URL:         siteBaseURL + "/blog",
+		Publisher:   sitePublisher(),
+		InLanguage:  "ja",
+	}
+	if path == "/blog" {
+		return jsonLD(blog, blogBreadcrumbs())
+	}
+	return jsonLD(blog, blogBreadcrumbs(jsonld.Crumb{Name: name, URL: siteBaseURL + path}))
+}
+
+// 記事ページ（BlogPosting とパンくず）
+func blogPostJSONLD(post *models.BlogPost, description string) template.HTML {
+	link := siteBaseURL + "/blog/" + post.Slug
+	if post.Canonical != "" {
+		link = post.Canonical
+	}
+
+	var image *jsonld.ImageObject
+	if link := postOGImageURL(post); link != "" {
+		image = jsonld.NewImage(link, 0, 0)
+		if post.OGImage == "" {
+			image.Width, image.Height = 1200, 630
+		}
+	}
+
+	posting := &jsonld.BlogPosting{
+		Context:          jsonld.Context,
+		Type:             "BlogPosting",
+		Headline:         post.Title,
+		Description:      description,
+		URL:              link,
+		MainEntityOfPage: link,
+		DatePublished:    post.CreatedDate.Format(time.RFC3339),
+		DateModified:     postUpdated(post).Format(time.RFC3339),
+		Author:           &jsonld.Person{Type: "Person", Name: feedAuthor, URL: siteBaseURL + "/about"},
+		Publisher:        sitePublisher(),
+		Image:            image,
+		ArticleSection:   post.Category,
+		Keywords:         post.Tags,
+		InLanguage:       "ja",
+	}
+	if post.Stats != nil {
+		posting.WordCount = post.Stats.WordCount
+	}
+
+	var crumbs []jsonld.Crumb
+	if post.Category != "" {
+		crumbs = append(crumbs, jsonld.Crumb{Name: post.Category, URL: siteBaseURL + "/blog/categories/" + url.PathEscape(post.Category)})
+	}
+	crumbs = append(crumbs, jsonld.Crumb{Name: post.Title, URL: link})
+	return jsonLD(posting, blogBreadcrumbs(crumbs...))
+}
+
+var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// FAQの回答（タグを除いたテキスト）
+func faqAnswerText(item faqItem) string {
+	return strings.TrimSpace(html.UnescapeString(htmlTagPattern.ReplaceAllString(string(item.Answer), "")))
+}
+
+// FAQページ
+func faqJSONLD(items []faqItem) template.HTML {
+	page := &jsonld.FAQPage{Context: jsonld.Context, Type: "FAQPage"}
+	for _, item := range items {
+		page.MainEntity = append(page.MainEntity, jsonld.NewQuestion(item.Question, faqAnswerText(item)))
+	}
+	return jsonLD(page)
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"encoding/json"
+	"html/template"
+	"strings"
+	"testing"
+	"time"
+
+	"infohiroki-go/src/models"
+)
+
+const scriptInjection = `</script><script>alert(1)</script>`
+
+// scriptタグごとにJSONを取り出す（値に </script> が残っていればタグの数が合わなくなる）
+func parseJSONLD(t *testing.T, script template.HTML) []map[string]interface{} {
+	t.Helper()
+	s := string(script)
+	opens := strings.Count(s, `<script type="application/ld+json">`)
+	if closes := strings.Count(strings.ToLower(s), "</script"); closes != opens {
+		t.Fatalf("%d script tags but %d closing tags:\n%s", opens, closes, s)
+	}
+	if strings.Contains(s, "<script>") {
+		t.Fatalf("unescaped script tag in output:\n%s", s)
+	}
+
+	var objects []map[string]interface{}
+	for _, part := range strings.Split(s, `<script type="application/ld+json">`)[1:] {
+		body, _, ok := strings.Cut(part, "</script>")
+		if !ok {
+			t.Fatalf("unterminated script tag:\n%s", s)
+		}
+		var object map[string]interface{}
+		if err := json.Unmarshal([]byte(body), &object); err != nil {
+			t.Fatalf("invalid JSON-LD: %v\n%s", err, body)
+		}
+		objects = append(objects, object)
+	}
+	return objects
+}
+
+// 必須項目が空でない文字列であること（path は . 区切り）
+func requireFields(t *testing.T, object map[string]interface{}, paths ...string) {
+	t.Helper()
+	for _, path := range paths {
+		var value interface{} = object
+		for _, key := range strings.Split(path, ".") {
+			m, ok := value.(map[string]interface{})
+			if !ok {
+				value = nil
+				break
+			}
+			value = m[key]
+		}
+		if s, ok := value.(string); !ok || s == "" {
+			t.Errorf("%v: %s = %#v, want a non-empty string", object["@type"], path, value)
+		}
+	}
+}
+
+func checkBreadcrumbs(t *testing.T, list map[string]interface{}, wantLast string) {
+	t.Helper()
+	requireFields(t, list, "@context", "@type")
+	if list["@type"] != "BreadcrumbList" {
+		t.Fatalf("@type = %v, want BreadcrumbList", list["@type"])
+	}
+	items, _ := list["itemListElement"].([]interface{})
+	if len(items) < 2 {
+		t.Fatalf("itemListElement has %d items", len(items))
+	}
+	for i, raw := range items {
+		item := raw.(map[string]interface{})
+		requireFields(t, item, "@type", "name", "item")
+		if position, _ := item["position"].(float64); int(position) != i+1 {
+			t.Errorf("item %d: position = %v", i, item["position"])
+		}
+	}
+	if last := items[len(items)-1].(map[string]interface{}); last["name"] != wantLast {
+		t.Errorf("last crumb = %v, want %q", last["name"], wantLast)
+	}
+}
+
+func TestBlogPostJSONLD(t *testing.T) {
+	post := &models.BlogPost{
+		Slug:        "2025-01-01-jsonld-test",
+		Title:       "JSON-LD " + scriptInjection,
+		Category:    "ai",
+		Tags:        []string{"go"},
+		CreatedDate: time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC),
+	}
+	description := "説明 " + scriptInjection
+
+	objects := parseJSONLD(t, blogPostJSONLD(post, description))
+	if len(objects) != 2 {
+		t.Fatalf("got %d objects, want BlogPosting and BreadcrumbList", len(objects))
+	}
+
+	posting := objects[0]
+	if posting["@type"] != "BlogPosting" || posting["@context"] != "https://schema.org" {
+		t.Fatalf("@type = %v, @context = %v", posting["@type"], posting["@context"])
+	}
+	requireFields(t, posting, "headline", "description", "url", "mainEntityOfPage", "datePublished", "dateModified",
+		"author.@type", "author.name", "publisher.@type", "publisher.name", "publisher.logo.url", "inLanguage")
+	if posting["headline"] != post.Title || posting["description"] != description {
+		t.Errorf("headline/description not preserved: %v / %v", posting["headline"], posting["description"])
+	}
+	if _, err := time.Parse(time.RFC3339, posting["datePublished"].(string)); err != nil {
+		t.Errorf("datePublished: %v", err)
+	}
+
+	checkBreadcrumbs(t, objects[1], post.Title)
+}
+
+func TestBlogListJSONLD(t *testing.T) {
+	objects := parseJSONLD(t, blogListJSONLD("/blog/tags/x", "タグ "+scriptInjection))
+	if len(objects) != 2 {
+		t.Fatalf("got %d objects, want Blog and BreadcrumbList", len(objects))
+	}
+	requireFields(t, objects[0], "@context", "@type", "name", "url", "publisher.name")
+	checkBreadcrumbs(t, objects[1], "タグ "+scriptInjection)
+}
+
+func TestFAQJSONLD(t *testing.T) {
+	items := append([]faqItem{{Question: "質問 " + scriptInjection, Answer: template.HTML("<strong>回答</strong> &lt;/script&gt;")}}, faqItems...)
+	objects := parseJSONLD(t, faqJSONLD(items))
+	if len(objects) != 1 {
+		t.Fatalf("got %d objects, want FAQPage", len(objects))
+	}
+
+	page := objects[0]
+	requireFields(t, page, "@context", "@type")
+	if page["@type"] != "FAQPage" {
+		t.Fatalf("@type = %v, want FAQPage", page["@type"])
+	}
+	questions, _ := page["mainEntity"].([]interface{})
+	if len(questions) != len(items) {
+		t.Fatalf("mainEntity has %d questions, want %d", len(questions), len(items))
+	}
+	for _, raw := range questions {
+		question := raw.(map[string]interface{})
+		requireFields(t, question, "@type", "name", "acceptedAnswer.@type", "acceptedAnswer.text")
+		if text := question["acceptedAnswer"].(map[string]interface{})["text"].(string); strings.Contains(text, "<strong>") {
+			t.Errorf("answer keeps HTML tags: %q", text)
+		}
+	}
+	first := questions[0].(map[string]interface{})
+	if first["name"] != items[0].Question || first["acceptedAnswer"].(map[string]interface{})["text"] != "回答 </script>" {
+		t.Errorf("first question = %v", first)
+	}
+}
+
+func TestOrganizationJSONLD(t *testing.T) {
+	objects := parseJSONLD(t, jsonLD(siteOrganization(), siteProfessionalService()))
+	if len(objects) != 2 {
+		t.Fatalf("got %d objects", len(objects))
+	}
+
+	organization := objects[0]
+	if organization["@type"] != "Organization" {
+		t.Fatalf("@type = %v, want Organization", organization["@type"])
+	}
+	requireFields(t, organization, "@context", "@id", "name", "url", "logo.url", "contactPoint.@type", "contactPoint.contactType")
+
+	service := objects[1]
+	if service["@type"] != "ProfessionalService" {
+		t.Fatalf("@type = %v, want ProfessionalService", service["@type"])
+	}
+	requireFields(t, service, "@context", "@id", "name", "url", "image", "priceRange", "hasOfferCatalog.@type", "hasOfferCatalog.name")
+	offers, _ := service["hasOfferCatalog"].(map[string]interface{})["itemListElement"].([]interface{})
+	if len(offers) == 0 {
+		t.Fatal("hasOfferCatalog has no offers")
+	}
+	for _, raw := range offers {
+		offer := raw.(map[string]interface{})
+		requireFields(t, offer, "@type", "itemOffered.@type", "itemOffered.name", "priceSpecification.@type", "priceSpecification.priceCurrency")
+	}
+}
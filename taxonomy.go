@@ -0,0 +1,150 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"infohiroki-go/src/content"
+	"infohiroki-go/src/models"
+)
+
+// タグ・カテゴリによる絞り込み条件
+type postFilter struct {
+	Tags     []string
+	Category string
+	Series   string
+	Year     int // 作成年（0なら指定なし）
+	Month    int // 作成月（Yearと併用）
+}
+
+func (f postFilter) isEmpty() bool {
+	return len(f.Tags) == 0 && f.Category == "" && f.Series == "" && f.Year == 0
+}
+
+// すべてのタグを持ち、カテゴリが一致する記事か判定
+func (f postFilter) matches(post *models.BlogPost) bool {
+	if f.Category != "" && post.Category != models.NormalizeTag(f.Category) {
+		return false
+	}
+	if f.Series != "" && post.Series != models.NormalizeTag(f.Series) {
+		return false
+	}
+	if f.Year != 0 && post.CreatedDate.Year() != f.Year {
+		return false
+	}
+	if f.Month != 0 && int(post.CreatedDate.Month()) != f.Month {
+		return false
+	}
+	for _, tag := range f.Tags {
+		if !post.HasTag(tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// タグクラウドの1項目
+type tagCloudItem struct {
+	Name  string
+	Count int
+	URL   string
+	Level int // 1〜5（件数に応じた文字サイズ）
+}
+
+// 件数の多さに応じてレベルを割り当て
+func buildTagCloud(counts []content.TermCount, basePath string) []tagCloudItem {
+	maxCount := 1
+	for _, term := range counts {
+		if term.Count > maxCount {
+			maxCount = term.Count
+		}
+	}
+
+	items := make([]tagCloudItem, len(counts))
+	for i, term := range counts {
+		items[i] = tagCloudItem{
+			Name:  term.Name,
+			Count: term.Count,
+			URL:   basePath + url.PathEscape(term.Name),
+			Level: 1 + term.Count*4/maxCount,
+		}
+	}
+	return items
+}
+
+// タグ一覧
+func tagIndexPage(c *gin.Context) {
+	renderTermIndex(c, "/blog/tags", "タグ一覧 | infoHiroki", "🏷️ タグ一覧")
+}
+
+// カテゴリ一覧
+func categoryIndexPage(c *gin.Context) {
+	renderTermIndex(c, "/blog/categories", "カテゴリ一覧 | infoHiroki", "📂 カテゴリ一覧")
+}
+
+func renderTermIndex(c *gin.Context, path string, title string, heading string) {
+	snap := store.Snapshot()
+	now := time.Now()
+
+	c.HTML(http.StatusOK, "blog.html", gin.H{
+		"title":           title,
+		"heading":         heading,
+		"page":            "blog",
+		"termIndex":       true,
+		"tagCloud":        buildTagCloud(snap.Tags(now), "/blog/tags/"),
+		"categories":      buildTagCloud(snap.Categories(now), "/blog/categories/"),
+		"metaDescription": "infoHirokiブログのタグ・カテゴリ一覧",
+		"ogTitle":         title,
+		"ogDescription":   "生成AI・DX導入支援の技術ブログ",
+		"ogType":          "website",
+		"jsonLD":          blogListJSONLD(path, strings.TrimSuffix(title, " | infoHiroki")),
+	})
+}
+
+// タグ別記事一覧
+func tagPage(c *gin.Context) {
+	tag := models.NormalizeTag(c.Param("tag"))
+	if len(store.Snapshot().WithTag(tag, time.Now())) == 0 {
+		renderNotFound(c)
+		return
+	}
+
+	renderBlogList(c, blogListing{
+		path:        "/blog/tags/" + url.PathEscape(tag),
+		title:       "#" + tag + " の記事一覧 | infoHiroki",
+		heading:     "🏷️ #" + tag,
+		description: "infoHirokiブログの「" + tag + "」タグが付いた記事一覧",
+		filter:      postFilter{Tags: []string{tag}},
+	})
+}
+
+// カテゴリ別記事一覧
+func categoryPage(c *gin.Context) {
+	category := models.NormalizeTag(c.Param("category"))
+	if len(store.Snapshot().InCategory(category, time.Now())) == 0 {
+		renderNotFound(c)
+		return
+	}
+
+	renderBlogList(c, blogListing{
+		path:        "/blog/categories/" + url.PathEscape(category),
+		title:       category + " カテゴリの記事一覧 | infoHiroki",
+		heading:     "📂 " + category,
+		description: "infoHirokiブログの「" + category + "」カテゴリの記事一覧",
+		filter:      postFilter{Category: category},
+	})
+}
+
+// タグ・カテゴリ一覧API（件数付き）
+func listTerms(c *gin.Context) {
+	snap := store.Snapshot()
+	now := time.Now()
+
+	c.JSON(http.StatusOK, gin.H{
+		"tags":       snap.Tags(now),
+		"categories": snap.Categories(now),
+	})
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"infohiroki-go/src/content"
+	"infohiroki-go/src/models"
+)
+
+func seedTaxonomyPosts(t *testing.T) {
+	seedPosts(t,
+		models.BlogPost{Slug: "go-web", Title: "GoでWeb開発", Tags: []string{"go", "web"}, Category: "dev", Published: true, CreatedDate: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		models.BlogPost{Slug: "go-cli", Title: "GoでCLI", Tags: []string{"go"}, Category: "dev", Published: true, CreatedDate: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+		models.BlogPost{Slug: "ai-intro", Title: "生成AI入門", Tags: []string{"生成ai"}, Category: "ai", Published: true, CreatedDate: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		models.BlogPost{Slug: "draft", Title: "下書き", Tags: []string{"secret"}, Category: "hidden", CreatedDate: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+	)
+}
+
+func TestTaxonomyRoutes(t *testing.T) {
+	seedTaxonomyPosts(t)
+
+	tests := []struct {
+		path    string
+		status  int
+		want    []string
+		notWant []string
+	}{
+		{"/blog/tags/go", http.StatusOK, []string{"GoでWeb開発", "GoでCLI"}, []string{"生成AI入門"}},
+		{"/blog/tags/Go", http.StatusOK, []string{"GoでWeb開発", "GoでCLI"}, nil},
+		{"/blog/tags/%E7%94%9F%E6%88%90ai", http.StatusOK, []string{"生成AI入門"}, []string{"GoでCLI"}},
+		{"/blog/categories/dev", http.StatusOK, []string{"GoでWeb開発", "GoでCLI"}, []string{"生成AI入門"}},
+		{"/blog/tags", http.StatusOK, []string{"/blog/tags/go", "/blog/categories/dev"}, []string{"/blog/tags/secret", "/blog/categories/hidden"}},
+		{"/blog/categories", http.StatusOK, []string{"/blog/categories/ai"}, nil},
+		// 公開記事のないタグ・カテゴリは404
+		{"/blog/tags/secret", http.StatusNotFound, nil, nil},
+		{"/blog/tags/none", http.StatusNotFound, nil, nil},
+		{"/blog/categories/hidden", http.StatusNotFound, nil, nil},
+	}
+	for _, tt := range tests {
+		w := get(t, tt.path)
+		if w.Code != tt.status {
+			t.Errorf("%s: status %d, want %d", tt.path, w.Code, tt.status)
+			continue
+		}
+		body := w.Body.String()
+		for _, want := range tt.want {
+			if !strings.Contains(body, want) {
+				t.Errorf("%s: missing %q", tt.path, want)
+			}
+		}
+		for _, notWant := range tt.notWant {
+			if strings.Contains(body, notWant) {
+				t.Errorf("%s: unexpected %q", tt.path, notWant)
+			}
+		}
+	}
+}
+
+func TestListTermsAPI(t *testing.T) {
+	seedTaxonomyPosts(t)
+
+	w := get(t, "/api/tags")
+	var body struct {
+		Tags       []content.TermCount `json:"tags"`
+		Categories []content.TermCount `json:"categories"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	// 件数の多い順（同数は名前順）、下書きのみのタグは含めない
+	want := []content.TermCount{{Name: "go", Count: 2}, {Name: "web", Count: 1}, {Name: "生成ai", Count: 1}}
+	if len(body.Tags) != len(want) {
+		t.Fatalf("tags = %+v, want %+v", body.Tags, want)
+	}
+	for i := range want {
+		if body.Tags[i] != want[i] {
+			t.Errorf("tags[%d] = %+v, want %+v", i, body.Tags[i], want[i])
+		}
+	}
+	if len(body.Categories) != 2 || body.Categories[0] != (content.TermCount{Name: "dev", Count: 2}) {
+		t.Errorf("categories = %+v", body.Categories)
+	}
+}
+
+func TestBuildTagCloud(t *testing.T) {
+	items := buildTagCloud([]content.TermCount{{Name: "go", Count: 8}, {Name: "生成 ai", Count: 4}, {Name: "web", Count: 1}}, "/blog/tags/")
+	wantLevels := []int{5, 3, 1}
+	for i, item := range items {
+		if item.Level != wantLevels[i] {
+			t.Errorf("%s: level %d, want %d", item.Name, item.Level, wantLevels[i])
+		}
+	}
+	if items[1].URL != "/blog/tags/%E7%94%9F%E6%88%90%20ai" {
+		t.Errorf("url = %s", items[1].URL)
+	}
+}
+
+func TestPostFilterMatches(t *testing.T) {
+	post := &models.BlogPost{Tags: []string{"go", "web"}, Category: "dev", Series: "intro", CreatedDate: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)}
+	tests := []struct {
+		filter postFilter
+		want   bool
+	}{
+		{postFilter{}, true},
+		{postFilter{Tags: []string{"go", "web"}}, true},
+		{postFilter{Tags: []string{"go", "rust"}}, false},
+		{postFilter{Category: "Dev"}, true},
+		{postFilter{Category: "ai"}, false},
+		{postFilter{Series: "intro"}, true},
+		{postFilter{Year: 2024, Month: 5}, true},
+		{postFilter{Year: 2024, Month: 6}, false},
+		{postFilter{Year: 2023}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.matches(post); got != tt.want {
+			t.Errorf("%+v.matches = %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// 連続した書き込みイベントをまとめる待ち時間
+const articleReloadDelay = 200 * time.Millisecond
+
+// articlesディレクトリを監視し、変更されたファイルだけを読み直す。stop で監視を止める（リロード中なら終わるまで待つ）
+func watchArticles(set *articleSet) (stop func(), err error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	// fsnotifyはサブディレクトリを自動で監視しないため個別に登録
+	err = filepath.Walk(set.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return watcher.Add(path)
+		}
+		return nil
+	})
+	if err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		pending := map[string]bool{}
+		rulesChanged := false
+		redirectsChanged := false
+		timer := time.NewTimer(articleReloadDelay)
+		timer.Stop()
+
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				// 新しく作られたディレクトリも監視対象に追加
+				if event.Has(fsnotify.Create) {
+					if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+						watcher.Add(event.Name)
+						addArticlesIn(event.Name, pending)
+						timer.Reset(articleReloadDelay)
+						continue
+					}
+				}
+
+				// ディレクトリごと削除・移動された場合は配下の記事をまとめて再確認
+				if event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+					for _, path := range set.pathsUnder(event.Name) {
+						pending[path] = true
+					}
+				}
+
+				// 分類ルールの変更は全記事に再適用
+				if filepath.Clean(event.Name) == filepath.Clean(set.rulesPath) && event.Op != fsnotify.Chmod {
+					rulesChanged = true
+				}
+
+				// リダイレクト設定の変更
+				if filepath.Clean(event.Name) == filepath.Clean(set.redirectsPath) && event.Op != fsnotify.Chmod {
+					redirectsChanged = true
+				}
+
+				if isArticleFile(event.Name) && event.Op != fsnotify.Chmod {
+					pending[event.Name] = true
+				}
+				if len(pending) > 0 || rulesChanged || redirectsChanged {
+					timer.Reset(articleReloadDelay)
+				}
+
+			case <-timer.C:
+				if redirectsChanged {
+					set.loadRedirects()
+					redirectsChanged = false
+					if !rulesChanged && len(pending) == 0 {
+						set.publishRedirects()
+						continue
+					}
+				}
+				if rulesChanged {
+					set.loadRules()
+					rulesChanged = false
+					if len(pending) == 0 {
+						set.publish()
+						continue
+					}
+				}
+				if len(pending) == 0 {
+					continue
+				}
+				paths := make([]string, 0, len(pending))
+				for path := range pending {
+					paths = append(paths, path)
+				}
+				pending = map[string]bool{}
+
+				set.reload(paths)
+				set.publish()
+				fmt.Printf("✅ 記事をリロード: %d件のファイル変更を反映\n", len(paths))
+
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				fmt.Printf("⚠️ 記事ディレクトリ監視エラー: %v\n", err)
+			}
+		}
+	}()
+
+	fmt.Printf("👀 記事ディレクトリを監視中: %s\n", set.dir)
+	return func() {
+		watcher.Close()
+		<-done
+	}, nil
+}
+
+// ディレクトリ配下の記事ファイルをリロード対象に追加
+func addArticlesIn(dir string, pending map[string]bool) {
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() && isArticleFile(path) {
+			pending[path] = true
+		}
+		return nil
+	})
+}
+
+// 指定ディレクトリ配下で読み込み済みの記事ファイルを列挙
+func (a *articleSet) pathsUnder(dir string) []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	prefix := dir + string(filepath.Separator)
+	var paths []string
+	for path := range a.files {
+		if strings.HasPrefix(path, prefix) {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// 条件を満たすまでスナップショットを確認（リロードは articleReloadDelay 後）
+func waitForStore(t *testing.T, what string, ok func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !ok() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestWatchArticles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(path, body string) {
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	title := func(slug string) string {
+		post, _, _ := store.Snapshot().Lookup(slug)
+		return post.Title
+	}
+
+	write(filepath.Join(dir, "2024-01-01-a.md"), "# A\n")
+	set := newArticleSet(dir, filepath.Join(dir, "taxonomy.yml"), filepath.Join(dir, "redirects.yml"))
+	set.loadAll()
+	set.publish()
+	t.Cleanup(func() { store.ReplacePosts(nil) })
+
+	stop, err := watchArticles(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(stop)
+
+	// 追加
+	write(filepath.Join(dir, "2024-01-02-b.md"), "# B\n")
+	waitForStore(t, "new article", func() bool { return title("2024-01-02-b") == "B" })
+
+	// 変更
+	write(filepath.Join(dir, "2024-01-01-a.md"), "# A2\n")
+	waitForStore(t, "edited article", func() bool { return title("2024-01-01-a") == "A2" })
+
+	// 新しいサブディレクトリ内の記事
+	sub := filepath.Join(dir, "sub")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	write(filepath.Join(sub, "2024-01-03-c.md"), "# C\n")
+	waitForStore(t, "article in a new directory", func() bool { return title("2024-01-03-c") == "C" })
+
+	// 削除（ディレクトリごと）
+	if err := os.Remove(filepath.Join(dir, "2024-01-02-b.md")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(sub); err != nil {
+		t.Fatal(err)
+	}
+	waitForStore(t, "removed articles", func() bool { return store.Snapshot().Len() == 1 })
+	if title("2024-01-01-a") != "A2" {
+		t.Error("unchanged article lost after removals")
+	}
+}